@@ -0,0 +1,144 @@
+package main
+
+import (
+	"math"
+	"sort"
+
+	"github.com/ScrappersIO/Player-Samples/client"
+	"github.com/ScrappersIO/Player-Samples/cluster"
+	"github.com/ScrappersIO/Player-Samples/formation"
+	"github.com/ScrappersIO/Player-Samples/geom"
+)
+
+func main() {
+	client.Main(runStrategy)
+}
+
+func runStrategy() {
+
+	// COUNTERPUNCH
+	// - Watch what the enemy is doing and play its counter.
+	// - Against a swarm that scatters then converges, hold a
+	//   dish at the edge of range and let them run into it.
+	// - Against a line, circle its head and shoot it first.
+	// - Against an arc holding range, charge a flank in a wedge.
+	// - Until we can tell, hold the dish.
+
+	assigner := formation.NewAssigner()
+	power := client.NewPowerManager(client.AdaptivePower)
+	orbits := make(map[int]*client.Orbiter)
+
+	// Put everyone in formation, shooting at targets in
+	// order without overkill.
+	deploy := func(myBots []*client.GDBBot, f formation.Formation, targets []*client.GDBBot) {
+		members := make([]formation.Member, len(myBots))
+		for i, bot := range myBots {
+			members[i] = formation.Member{ID: bot.BID, Pos: bot.Pos()}
+		}
+		slots := assigner.Assign(members, f.Slots(len(myBots)))
+		fpow := make(map[int]int)
+		for _, bot := range myBots {
+			fpow[bot.BID] = power.Plan(bot, slots[bot.BID], targets[0]).F
+		}
+		fire := client.AllocateFire(myBots, targets, fpow)
+		for _, bot := range myBots {
+			client.Send(bot.MoveWithin(slots[bot.BID], client.Clamp))
+			client.Send(bot.Target(fire[bot.BID]))
+			client.SetTarget(bot, fire[bot.BID])
+			client.Send(power.Power(bot, slots[bot.BID], fire[bot.BID]))
+		}
+	}
+
+	holdDish := func() {
+		myBots, theirBots := client.GDB.MyBots(), client.GDB.TheirBots()
+		if len(myBots) == 0 || len(theirBots) == 0 {
+			return
+		}
+
+		// Face the nearest group from just inside effective range
+		ours := middle(myBots)
+		group, _ := client.NearestCluster(client.GDB.EnemyClusters(), ours)
+		dist := client.Weapons.EffectiveRange() * 0.9
+		angle := group.Centroid.AngleTo(ours)
+		dish := formation.Formation{
+			Shape:   formation.Arc(dist),
+			Anchor:  group.Centroid.Add(geom.Polar(dist, angle)),
+			Facing:  angle + math.Pi,
+			Spacing: client.BotDiam,
+		}
+		deploy(myBots, dish, byDistance(theirBots, ours))
+	}
+
+	huntHead := func() {
+		myBots, theirBots := client.GDB.MyBots(), client.GDB.TheirBots()
+		if len(myBots) == 0 || len(theirBots) == 0 {
+			return
+		}
+
+		// The head of the line is whichever end isn't
+		// following anyone
+		head, _ := client.LineHead(theirBots)
+
+		// Everyone circles it, half each way round
+		for i, bot := range myBots {
+			orbit, ok := orbits[bot.BID]
+			if !ok {
+				orbit = client.NewOrbiter(client.BotDiam*3, client.Weapons.EffectiveRange()*0.8)
+				if i%2 == 1 {
+					orbit.Dir = client.Clockwise
+				}
+				orbits[bot.BID] = orbit
+			}
+			dest := orbit.Next(bot, head)
+			client.Send(bot.Move(dest))
+			client.Send(bot.Target(head))
+			client.SetTarget(bot, head)
+			client.Send(power.Power(bot, dest, head))
+		}
+	}
+
+	chargeFlank := func() {
+		myBots, theirBots := client.GDB.MyBots(), client.GDB.TheirBots()
+		if len(myBots) == 0 || len(theirBots) == 0 {
+			return
+		}
+
+		// Their closest bot is the end of the arc nearest us,
+		// so drive a wedge into it and work along from there.
+		ours := middle(myBots)
+		targets := byDistance(theirBots, ours)
+		flank := targets[0]
+		angle := flank.Pos().AngleTo(ours)
+		wedge := formation.Formation{
+			Shape:   formation.Wedge,
+			Anchor:  flank.Pos().Add(geom.Polar(client.BotDiam*2, angle)),
+			Facing:  angle + math.Pi,
+			Spacing: client.BotDiam,
+		}
+		deploy(myBots, wedge, byDistance(theirBots, flank.Pos()))
+	}
+
+	client.NewMeta(holdDish, map[client.Archetype]client.Tactic{
+		client.ArchScatter: holdDish,
+		client.ArchLine:    huntHead,
+		client.ArchArc:     chargeFlank,
+	}).Run()
+}
+
+// middle returns the average position of bots.
+func middle(bots []*client.GDBBot) geom.Vec2 {
+	points := make([]geom.Vec2, len(bots))
+	for i, bot := range bots {
+		points[i] = bot.Pos()
+	}
+	return cluster.Centroid(points)
+}
+
+// byDistance returns bots sorted closest to p first.
+func byDistance(bots []*client.GDBBot, p geom.Vec2) []*client.GDBBot {
+	sorted := append([]*client.GDBBot{}, bots...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Pos().Dist(p) < sorted[j].Pos().Dist(p)
+	})
+	return sorted
+}
@@ -0,0 +1,101 @@
+package client
+
+import (
+	"sort"
+)
+
+// DefaultDamagePerFPow is our guess at how much health a
+// shot takes off an unshielded bot per point of FPow, until
+// Weapons has seen some.
+const DefaultDamagePerFPow float64 = 0.25
+
+// FirePower returns the FPow we last gave one of our bots,
+// or zero if we never powered it.
+func (b *GDBBot) FirePower() int {
+	lastSentMu.Lock()
+	defer lastSentMu.Unlock()
+	return lastSent[sentKey{"POWER", b.BID}].FPow
+}
+
+// ExpectedDamage returns how much health one shot from our
+// bot is likely to take off target with the FPow we last
+// gave it. See DamageWith.
+func (b *GDBBot) ExpectedDamage(target *GDBBot) float64 {
+	return b.DamageWith(b.FirePower(), target)
+}
+
+// DamageWith returns how much health one shot from our bot
+// at fpow is likely to take off target: nothing if it's out
+// of effective range, and less if its shield is up (by as
+// much as we reckon its SPow is, if we've worked that out
+// since it went up).
+func (b *GDBBot) DamageWith(fpow int, target *GDBBot) float64 {
+	if b.Pos().Dist(target.Pos()) > Weapons.EffectiveRange() {
+		return 0
+	}
+	damage := Weapons.Damage(fpow)
+	switch {
+	case !target.Shield:
+	case target.Inferred.SKnown && target.Inferred.S > 0:
+		damage *= shieldFactor(target.Inferred.S)
+	default:
+		damage /= shieldedToughness
+	}
+	return damage
+}
+
+// AllocateFire shares out shooters among targets, which
+// should be in order of priority. Each target gets just
+// enough shooters to finish it off in one volley, and the
+// rest move on to the next target instead of wasting shots
+// on a bot that's already dead. Shooters that can't hurt
+// any target that still needs it go after the first one.
+// fpow is the FPow each shooter is about to be given, by
+// BID; shooters not in it (or all of them, if it's nil) are
+// judged by the FPow we last gave them. It returns the
+// target for each shooter by BID.
+func AllocateFire(shooters, targets []*GDBBot, fpow map[int]int) map[int]*GDBBot {
+	result := make(map[int]*GDBBot)
+	if len(targets) == 0 {
+		return result
+	}
+	damage := func(shooter, target *GDBBot) float64 {
+		if f, ok := fpow[shooter.BID]; ok {
+			return shooter.DamageWith(f, target)
+		}
+		return shooter.ExpectedDamage(target)
+	}
+
+	free := append([]*GDBBot{}, shooters...)
+	for _, target := range targets {
+		need := float64(target.Health)
+
+		// Whoever hits hardest goes first, so the fewest
+		// shooters get tied up on each target.
+		sort.SliceStable(free, func(i, j int) bool {
+			return damage(free[i], target) > damage(free[j], target)
+		})
+		taken := 0
+		for _, shooter := range free {
+			if need <= 0 {
+				break
+			}
+			d := damage(shooter, target)
+			if d <= 0 {
+				break
+			}
+			result[shooter.BID] = target
+			need -= d
+			taken++
+		}
+		free = free[taken:]
+
+		Trace(TraceStrategy, "Allocated fire", "tpid", target.PID, "tbid", target.BID,
+			"health", target.Health, "shooters", taken, "remaining", need)
+	}
+
+	for _, shooter := range free {
+		result[shooter.BID] = targets[0]
+	}
+	return result
+}
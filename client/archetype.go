@@ -0,0 +1,365 @@
+package client
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/ScrappersIO/Player-Samples/cluster"
+	"github.com/ScrappersIO/Player-Samples/geom"
+)
+
+// Archetype is a kind of strategy an opponent is playing.
+type Archetype string
+
+const (
+	// ArchUnknown is an opponent we can't place yet.
+	ArchUnknown Archetype = "unknown"
+	// ArchScatter scatters at the start, then converges on
+	// a target, like reckless abandon.
+	ArchScatter Archetype = "scatter-converge"
+	// ArchLine follows the leader in a line, like the
+	// danger noodle.
+	ArchLine Archetype = "line"
+	// ArchArc holds an arc at range, like the death dish
+	// and death star.
+	ArchArc Archetype = "arc"
+)
+
+const (
+	// How many recent samples the classifier judges by.
+	archWindow = 20
+	// How many samples the opening lasts, after which the
+	// label is settled: five seconds at Meta's tick, long
+	// enough to see a swarm scatter and start converging.
+	archOpening = 50
+)
+
+// How close together bots following each other in a line
+// are.
+const lineLink = BotDiam * 2.5
+
+// Classification is what the classifier makes of the enemy.
+type Classification struct {
+	Label      Archetype
+	Confidence float64               // 0 (a guess) to 1 (sure)
+	Scores     map[Archetype]float64 // Recent average for each
+}
+
+// Classifier watches the enemy through the opening of the
+// match and guesses which archetype it's playing. Once the
+// opening is over the label is settled for the rest of the
+// match: in the thick of a fight every archetype looks like
+// a brawl, and changing counters then would only throw away
+// the position the first one built up.
+type Classifier struct {
+	startSpread float64
+	maxSpread   float64
+	samples     []map[Archetype]float64
+	observed    int
+	settled     *Classification
+}
+
+// NewClassifier returns a Classifier that hasn't seen
+// anything yet.
+func NewClassifier() *Classifier {
+	return &Classifier{startSpread: -1}
+}
+
+// Observe takes a look at where the enemy is and how it's
+// moving. Call it regularly, e.g. every strategy tick. It
+// does nothing once the label is settled.
+func (c *Classifier) Observe() {
+	if c.settled != nil {
+		return
+	}
+	theirs, mine := GDB.TheirBots(), GDB.MyBots()
+	if len(theirs) == 0 || len(mine) == 0 {
+		return
+	}
+
+	points := make([]geom.Vec2, len(theirs))
+	for i, bot := range theirs {
+		points[i] = bot.Pos()
+	}
+	spread := cluster.Spread(points)
+	if c.startSpread < 0 {
+		c.startSpread = spread
+	}
+	c.maxSpread = math.Max(c.maxSpread, spread)
+
+	ours := make([]geom.Vec2, len(mine))
+	for i, bot := range mine {
+		ours[i] = bot.Pos()
+	}
+	home := cluster.Centroid(ours)
+
+	scores := map[Archetype]float64{
+		ArchScatter: c.scatterScore(theirs, spread, home),
+		ArchLine:    lineScore(theirs, home),
+		ArchArc:     arcScore(theirs, home),
+	}
+	c.samples = append(c.samples, scores)
+	if len(c.samples) > archWindow {
+		c.samples = c.samples[len(c.samples)-archWindow:]
+	}
+
+	c.observed++
+	if c.observed >= archOpening {
+		final := c.classify()
+		c.settled = &final
+		Trace(TraceStrategy, "Classification settled", "label", final.Label,
+			"confidence", final.Confidence, "scores", final.Scores)
+	}
+}
+
+// Settled reports whether the opening is over and the label
+// won't change any more.
+func (c *Classifier) Settled() bool {
+	return c.settled != nil
+}
+
+// Classify returns the archetype that has fitted best
+// lately, or for good once the opening is over. Confidence
+// is how far it's ahead of the next best, scaled down until
+// we've got a full window of samples.
+func (c *Classifier) Classify() Classification {
+	if c.settled != nil {
+		return *c.settled
+	}
+	return c.classify()
+}
+
+func (c *Classifier) classify() Classification {
+	result := Classification{Label: ArchUnknown, Scores: make(map[Archetype]float64)}
+	if len(c.samples) == 0 {
+		return result
+	}
+	for _, sample := range c.samples {
+		for arch, score := range sample {
+			result.Scores[arch] += score / float64(len(c.samples))
+		}
+	}
+
+	ranked := []Archetype{ArchScatter, ArchLine, ArchArc}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return result.Scores[ranked[i]] > result.Scores[ranked[j]]
+	})
+	best, next := result.Scores[ranked[0]], result.Scores[ranked[1]]
+	if best > 0 {
+		result.Label = ranked[0]
+		result.Confidence = (best - next) * float64(len(c.samples)) / archWindow
+	}
+	return result
+}
+
+// scatterScore is how much it looks like the enemy spread
+// out at the start and has since been coming back together,
+// heading for us.
+func (c *Classifier) scatterScore(theirs []*GDBBot, spread float64, home geom.Vec2) float64 {
+	scattered := clamp01((c.maxSpread - c.startSpread) / (BotDiam * 5))
+	converged := 0.0
+	if c.maxSpread > 0 {
+		converged = clamp01((c.maxSpread - spread) / c.maxSpread)
+	}
+	closing, moving := 0, 0
+	for _, bot := range theirs {
+		if vel, ok := bot.Velocity(); ok && vel.Len() > 1 {
+			moving++
+			if vel.Dot(home.Sub(bot.Pos())) > 0 {
+				closing++
+			}
+		}
+	}
+	if moving > 0 {
+		converged = math.Max(converged, float64(closing)/float64(moving))
+	}
+
+	// Scattering is half the story until they converge
+	return scattered * (0.5 + 0.5*converged)
+}
+
+// lineScore is how much the enemy looks like a line of bots
+// each following the one before, but not an arc.
+func lineScore(theirs []*GDBBot, home geom.Vec2) float64 {
+	if len(theirs) < 3 {
+		return 0
+	}
+	chain := float64(len(lineChain(theirs))-1) / float64(len(theirs)-1)
+	return chain * (1 - arcFit(theirs, home))
+}
+
+// lineChain follows bots along the line they're in, from
+// one end: the bot furthest from their middle, then each
+// time the nearest of the rest, until the line breaks. We
+// go by where they are, not their BIDs, as a line may be in
+// any order.
+func lineChain(bots []*GDBBot) []*GDBBot {
+	if len(bots) == 0 {
+		return nil
+	}
+	rest := append([]*GDBBot{}, bots...)
+	sort.Slice(rest, func(i, j int) bool {
+		return rest[i].BID < rest[j].BID
+	})
+	points := make([]geom.Vec2, len(rest))
+	for i, bot := range rest {
+		points[i] = bot.Pos()
+	}
+	middle := cluster.Centroid(points)
+
+	next := 0
+	for i, bot := range rest {
+		if bot.Pos().Dist(middle) > rest[next].Pos().Dist(middle) {
+			next = i
+		}
+	}
+	chain := make([]*GDBBot, 0, len(rest))
+	for next >= 0 {
+		last := rest[next]
+		chain = append(chain, last)
+		rest = append(rest[:next], rest[next+1:]...)
+		next = -1
+		for i, bot := range rest {
+			d := bot.Pos().Dist(last.Pos())
+			if d <= lineLink && (next < 0 || d < rest[next].Pos().Dist(last.Pos())) {
+				next = i
+			}
+		}
+	}
+	return chain
+}
+
+// LineHead returns the bot at the front of a line: the end
+// that isn't following the bot next to it. Followers head
+// for the bot in front, so the end moving more towards its
+// neighbour is the tail. If that doesn't settle it, the head
+// is the end that fired when we last heard from it, as lines
+// tend to do their shooting from the front.
+func LineHead(bots []*GDBBot) (*GDBBot, bool) {
+	chain := lineChain(bots)
+	switch len(chain) {
+	case 0:
+		return nil, false
+	case 1:
+		return chain[0], true
+	}
+	first, last := chain[0], chain[len(chain)-1]
+	following := func(end, neighbour *GDBBot) float64 {
+		vel, ok := end.Velocity()
+		if !ok || vel.Len() < 1 {
+			return 0
+		}
+		return vel.Norm().Dot(neighbour.Pos().Sub(end.Pos()).Norm())
+	}
+	f, l := following(first, chain[1]), following(last, chain[len(chain)-2])
+	switch {
+	case math.Abs(f-l) > 0.5 && f < l:
+		return first, true
+	case math.Abs(f-l) > 0.5:
+		return last, true
+	case last.Fired && !first.Fired:
+		return last, true
+	}
+	return first, true
+}
+
+// arcScore is how much the enemy looks like an arc around
+// us, some way off.
+func arcScore(theirs []*GDBBot, home geom.Vec2) float64 {
+	if len(theirs) < 3 {
+		return 0
+	}
+	mean := 0.0
+	for _, bot := range theirs {
+		mean += bot.Pos().Dist(home)
+	}
+	mean /= float64(len(theirs))
+	return arcFit(theirs, home) * clamp01(mean/(BotDiam*6))
+}
+
+// arcFit is how close the enemy all are to being the same
+// distance from home, as they would be in an arc facing it.
+func arcFit(theirs []*GDBBot, home geom.Vec2) float64 {
+	dists := make([]float64, len(theirs))
+	mean := 0.0
+	for i, bot := range theirs {
+		dists[i] = bot.Pos().Dist(home)
+		mean += dists[i]
+	}
+	mean /= float64(len(dists))
+	if mean == 0 {
+		return 0
+	}
+	variance := 0.0
+	for _, d := range dists {
+		variance += (d - mean) * (d - mean)
+	}
+	cv := math.Sqrt(variance/float64(len(dists))) / mean
+	return clamp01(1 - cv/0.3)
+}
+
+// clamp01 keeps v between 0 and 1.
+func clamp01(v float64) float64 {
+	return math.Min(math.Max(v, 0), 1)
+}
+
+// Tactic is one tick of a strategy.
+type Tactic func()
+
+// Meta is a strategy that watches what the enemy is doing
+// and plays the counter configured for it, or Default until
+// it's confident enough to tell. It can only change its
+// mind during the opening (see Classifier); after that it
+// plays the settled label's counter if the classifier is
+// confident of it, and otherwise carries on as it was.
+type Meta struct {
+	Classifier    *Classifier
+	Default       Tactic
+	Counters      map[Archetype]Tactic
+	MinConfidence float64
+	Tick          time.Duration
+}
+
+// NewMeta returns a Meta playing def until it can pick one
+// of counters.
+func NewMeta(def Tactic, counters map[Archetype]Tactic) *Meta {
+	return &Meta{
+		Classifier:    NewClassifier(),
+		Default:       def,
+		Counters:      counters,
+		MinConfidence: 0.3,
+		Tick:          time.Second / 10,
+	}
+}
+
+// Run plays until either side has no bots left.
+func (m *Meta) Run() {
+	playing := ArchUnknown
+	SetPhase("counter " + string(playing))
+	for len(GDB.MyBots()) > 0 && len(GDB.TheirBots()) > 0 {
+		m.Classifier.Observe()
+		class := m.Classifier.Classify()
+		Trace(TraceStrategy, "Classified enemy", "label", class.Label,
+			"confidence", class.Confidence, "scores", class.Scores)
+
+		// Only change our mind when sure of the new answer
+		label := playing
+		if class.Confidence >= m.MinConfidence {
+			label = class.Label
+		}
+		tactic, ok := m.Counters[label]
+		if !ok {
+			label, tactic = ArchUnknown, m.Default
+		}
+		if label != playing {
+			Trace(TraceStrategy, "Switching counter", "from", playing, "to", label,
+				"confidence", class.Confidence, "scores", class.Scores)
+			SetPhase("counter " + string(label))
+			playing = label
+		}
+
+		tactic()
+		time.Sleep(m.Tick)
+	}
+}
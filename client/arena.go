@@ -0,0 +1,131 @@
+package client
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ScrappersIO/Player-Samples/geom"
+)
+
+// ArenaSlack is how far past the bots seen so far an
+// inferred arena reaches, so bots can still push outwards
+// and find the real walls.
+const ArenaSlack float64 = BotDiam * 2
+
+// EdgePolicy decides what happens to a destination
+// outside the arena.
+type EdgePolicy int
+
+const (
+	// Clamp moves the destination to the closest point inside.
+	Clamp EdgePolicy = iota
+	// Reflect bounces the destination off the walls.
+	Reflect
+	// Shorten stops at the wall on the way to the destination.
+	Shorten
+)
+
+var (
+	// The arena's walls, configured or inferred.
+	arena geom.Rect
+	// Whether the arena came from -arena.
+	arenaConfigured bool
+	// Whether we've seen any bots to infer it from.
+	arenaSeen bool
+	// The area bots have been seen in.
+	arenaObserved geom.Rect
+	arenaMu       sync.Mutex
+)
+
+// SetArena fixes the arena's walls instead of inferring
+// them from where bots have been.
+func SetArena(walls geom.Rect) {
+	arenaMu.Lock()
+	defer arenaMu.Unlock()
+	arena = walls
+	arenaConfigured = true
+}
+
+// Arena returns the arena's walls and whether we know
+// anything about them yet. Unless they were configured they
+// are inferred from where bots have been, plus ArenaSlack.
+func Arena() (geom.Rect, bool) {
+	arenaMu.Lock()
+	defer arenaMu.Unlock()
+	if arenaConfigured {
+		return arena, true
+	}
+	if !arenaSeen {
+		return geom.Rect{}, false
+	}
+	return arenaObserved.Inset(-(BotDiam/2 + ArenaSlack)), true
+}
+
+// observeArena grows the inferred arena to include a bot at p.
+func observeArena(p geom.Vec2) {
+	arenaMu.Lock()
+	defer arenaMu.Unlock()
+	if !arenaSeen {
+		arenaObserved = geom.Rect{Min: p, Max: p}
+		arenaSeen = true
+		return
+	}
+	arenaObserved = arenaObserved.Union(p)
+}
+
+// KeepInside returns where a bot at from should head for
+// instead of dest so that it stays clear of the walls. If
+// we don't know the arena yet dest is returned as is.
+//
+// Inferred walls are only where bots have been so far, plus
+// ArenaSlack, so they always Shorten whatever the policy:
+// clamping or reflecting at them could turn a long move
+// round and send a bot back the way it came, while stopping
+// short still lets it push out and find the real walls.
+func KeepInside(from, dest geom.Vec2, policy EdgePolicy) geom.Vec2 {
+	walls, ok := Arena()
+	if !ok {
+		return dest
+	}
+	arenaMu.Lock()
+	configured := arenaConfigured
+	arenaMu.Unlock()
+	if !configured {
+		policy = Shorten
+	}
+
+	// A bot's center has to stay half a bot from the walls
+	inside := walls.Inset(BotDiam / 2)
+	switch policy {
+	case Reflect:
+		return inside.Reflect(dest)
+	case Shorten:
+		return inside.Cut(from, dest)
+	}
+	return inside.Clamp(dest)
+}
+
+// MoveWithin returns a command struct for movement to dest,
+// adjusted by policy so the bot stays inside the arena.
+func (b *GDBBot) MoveWithin(dest geom.Vec2, policy EdgePolicy) Command {
+	return b.Move(KeepInside(b.Pos(), dest, policy))
+}
+
+// parseRect parses "minX,minY,maxX,maxY".
+func parseRect(s string) (geom.Rect, error) {
+	parts := strings.Split(s, ",")
+	if len(parts) != 4 {
+		return geom.Rect{}, fmt.Errorf("arena %q should be minX,minY,maxX,maxY", s)
+	}
+	var n [4]float64
+	for i, part := range parts {
+		v, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return geom.Rect{}, fmt.Errorf("arena %q: %v", s, err)
+		}
+		n[i] = v
+	}
+	return geom.R(n[0], n[1], n[2], n[3]), nil
+}
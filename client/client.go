@@ -0,0 +1,315 @@
+// Package client contains everything the sample players have in
+// common: the protocol messages, the connection to the game and the
+// game database. A sample only has to supply its strategy.
+package client
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"os"
+	"sync"
+
+	"github.com/ScrappersIO/Player-Samples/geom"
+)
+
+const (
+	MaxHealth int     = 12
+	MaxPow    int     = 12
+	BotDiam   float64 = 60
+)
+
+var (
+	// TCP connection to game.
+	gameConn net.Conn
+	// Queue of incoming messages
+	msgQueue chan MsgQueueItem
+	// Where the match is recorded, if anywhere.
+	recorder *Recorder
+	// Last command of each kind sent to each bot.
+	lastSent   map[sentKey]Command
+	lastSentMu sync.Mutex
+	// Unknown message types we've already warned about.
+	unknownTypes map[string]bool
+)
+
+// sentKey identifies a kind of command for a bot.
+type sentKey struct {
+	Cmd string
+	BID int
+}
+
+// GDB is our game data storage location.
+var GDB GameDatabase
+
+// MsgQueueItem is a simple vehicle for TCP
+// data on the incoming message queue.
+type MsgQueueItem struct {
+	Msg string
+	Err error
+}
+
+// Command contains all the fields that a player might
+// pass as part of a command. Fill in the fields that
+// matter, then marshal into JSON and send.
+type Command struct {
+	Cmd  string
+	BID  int
+	X    int
+	Y    int
+	TPID int
+	TBID int
+	FPow int
+	MPow int
+	SPow int
+}
+
+// Msg is used to unmarshal every message in order
+// to check what type of message it is.
+type Msg struct {
+	Type string
+}
+
+// BotMsg is used to unmarshal a BOT representation
+// sent from the game.
+type BotMsg struct {
+	PID, BID   int
+	X, Y       int
+	Health     int
+	Fired      bool
+	HitX, HitY int
+	Scrap      int
+	Shield     bool
+}
+
+// Pos returns where the bot is.
+func (b BotMsg) Pos() geom.Vec2 {
+	return geom.FromInt(b.X, b.Y)
+}
+
+// HitPos returns where the bot's last shot landed.
+func (b BotMsg) HitPos() geom.Vec2 {
+	return geom.FromInt(b.HitX, b.HitY)
+}
+
+// ReadyMsg is used to unmarshal the READY
+// message sent from the game.
+type ReadyMsg struct {
+	PID  int
+	Bots []BotMsg
+}
+
+// Main parses the command line, connects to the game and
+// starts strategy once the game tells us it is ready. It
+// returns when the game closes the connection.
+func Main(strategy func()) {
+
+	var err error
+	GDB = GameDatabase{}
+	msgQueue = make(chan MsgQueueItem, 1200)
+	lastSent = make(map[sentKey]Command)
+	unknownTypes = make(map[string]bool)
+
+	// What port should we connect to?
+	var port, recordPath, metricsAddr, debugAddr string
+	var logLevel, logFormat, trace, arenaRect string
+	var space float64
+	flag.StringVar(&port, "port", "50000", "Port that Scrappers game is listening on.")
+	flag.StringVar(&recordPath, "record", "", "Record the match to this file.")
+	flag.StringVar(&metricsAddr, "metrics", "", "Serve Prometheus metrics on this address, e.g. localhost:9100.")
+	flag.StringVar(&debugAddr, "debug", "", "Serve game state and pprof on this address, e.g. localhost:6060.")
+	flag.StringVar(&logLevel, "log-level", "info", "Log level: debug, info, warn or error.")
+	flag.StringVar(&logFormat, "log-format", "text", "Log format: text or json.")
+	flag.StringVar(&arenaRect, "arena", "", "Arena walls as minX,minY,maxX,maxY. Inferred from bot positions if not set.")
+	flag.Float64Var(&space, "spacing", BotDiam, "How far apart to keep our bots' destinations. 0 turns separation off.")
+	flag.StringVar(&trace, "trace", "", "Comma separated subsystems to trace (net, msg, db, strategy, cmd) or all.")
+	flag.Parse()
+
+	// Set up logging before anything is logged
+	err = setupLogging(os.Stderr, logLevel, logFormat, trace)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		flag.Usage()
+		os.Exit(2)
+	}
+	if arenaRect != "" {
+		walls, err := parseRect(arenaRect)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			flag.Usage()
+			os.Exit(2)
+		}
+		SetArena(walls)
+	}
+	SetSpacing(space)
+
+	// Metrics and debugging are opt-in
+	if metricsAddr != "" {
+		serveMetrics(metricsAddr)
+	}
+	if debugAddr != "" {
+		serveDebug(debugAddr)
+	}
+
+	// Start recording before anything is sent or received
+	if recordPath != "" {
+		recorder, err = CreateRecorder(recordPath)
+		if err != nil {
+			fatal("Failed to create match recording", "path", recordPath, "err", err)
+		}
+		defer recorder.Close()
+	}
+
+	// Connect to the game
+	gameConn, err = net.Dial("tcp", ":"+port)
+	if err != nil {
+		fatal("Failed to connect to game", "port", port, "err", err)
+	}
+	defer gameConn.Close()
+
+	// Process messages off the incoming message queue
+	go processMsgs(strategy)
+
+	// Listen for message from the game, exit if connection
+	// closes, add message to message queue.
+	reader := bufio.NewReader(gameConn)
+	for {
+		msg, err := reader.ReadString('\n')
+		if err == io.EOF {
+			slog.Info("Game over (connection closed).")
+			GDB.logCredit()
+			return
+		}
+		Trace(TraceNet, "Received line", "line", msg)
+		if err == nil && recorder != nil {
+			recorder.Record(In, []byte(msg))
+		}
+		msgQueue <- MsgQueueItem{msg, err}
+	}
+}
+
+func processMsgs(strategy func()) {
+
+	for {
+		queueItem := <-msgQueue
+		jsonmsg := queueItem.Msg
+		err := queueItem.Err
+
+		if err != nil {
+			slog.Error("Unknown error reading from connection", "err", err)
+			continue
+		}
+
+		// Determine the type of message first
+		var msg Msg
+		err = json.Unmarshal([]byte(jsonmsg), &msg)
+		if err != nil {
+			metrics.countDecodeError()
+			slog.Error("Failed to unmarshal json message", "msg", jsonmsg, "err", err)
+			return
+		}
+		metrics.countReceived(msg.Type)
+
+		// Handle the message type
+
+		// The READY message should be the first we get. We
+		// process all the data, then kick off our strategy.
+		if msg.Type == "READY" {
+
+			// Unmarshal the data
+			var ready ReadyMsg
+			err = json.Unmarshal([]byte(jsonmsg), &ready)
+			if err != nil {
+				metrics.countDecodeError()
+				slog.Error("Failed to unmarshal json message", "msg", jsonmsg, "err", err)
+			}
+
+			Trace(TraceMsg, "Decoded READY", "msg", ready)
+
+			// Save our player ID
+			GDB.PID = ready.PID
+			slog.Info("Game ready", "pid", GDB.PID)
+			logEvent("ready", GDB.PID, 0, fmt.Sprintf("%v bots", len(ready.Bots)))
+
+			// Save the bots
+			for _, bot := range ready.Bots {
+				GDB.InsertUpdateBot(bot)
+			}
+			GDB.publish()
+
+			// Kick off our strategy
+			go strategy()
+
+			continue
+		}
+
+		// The BOT message is sent when something about a bot changes.
+		if msg.Type == "BOT" {
+
+			// Unmarshal the data
+			var bot BotMsg
+			err = json.Unmarshal([]byte(jsonmsg), &bot)
+			if err != nil {
+				metrics.countDecodeError()
+				slog.Error("Failed to unmarshal json message", "msg", jsonmsg, "err", err)
+			}
+
+			Trace(TraceMsg, "Decoded BOT", "msg", bot)
+
+			// Update or add the bot
+			GDB.InsertUpdateBot(bot)
+			GDB.publish()
+
+			continue
+		}
+
+		// If we've gotten to this point, then we
+		// were sent a message we don't understand.
+		// Only warn once per type; trace the rest.
+		if !unknownTypes[msg.Type] {
+			unknownTypes[msg.Type] = true
+			slog.Warn("Received unknown message type", "type", msg.Type)
+			logEvent("unknown", 0, 0, msg.Type)
+		}
+		Trace(TraceMsg, "Ignored unknown message", "type", msg.Type, "msg", jsonmsg)
+	}
+}
+
+// Send marshals a command to JSON and sends to the game.
+// Moves are spread out so our bots don't stack up on each
+// other (see SetSpacing), and power may be shifted into
+// shields for bots under fire (see SetReactiveShield).
+func Send(cmd Command) {
+	send(shieldPower(separate(cmd)))
+}
+
+// send sends a command as is, remembering it as the last
+// of its kind for the bot and counting it if it repeats the
+// one before.
+func send(cmd Command) {
+	key := sentKey{cmd.Cmd, cmd.BID}
+	lastSentMu.Lock()
+	last, ok := lastSent[key]
+	lastSent[key] = cmd
+	lastSentMu.Unlock()
+	if ok && last == cmd {
+		metrics.countRepeated()
+	}
+	metrics.countSent(cmd.Cmd)
+	Trace(TraceCmd, "Sending command", "cmd", cmd)
+
+	bytes, err := json.Marshal(cmd)
+	if err != nil {
+		fatal("Failed to marshal command into JSON", "cmd", cmd, "err", err)
+	}
+	if recorder != nil {
+		recorder.Record(Out, bytes)
+	}
+	bytes = append(bytes, []byte("\n")...)
+	Trace(TraceNet, "Sent line", "line", string(bytes))
+	gameConn.Write(bytes)
+}
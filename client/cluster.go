@@ -0,0 +1,77 @@
+package client
+
+import (
+	"sort"
+
+	"github.com/ScrappersIO/Player-Samples/cluster"
+	"github.com/ScrappersIO/Player-Samples/geom"
+)
+
+// ClusterReach is how close bots have to be to count as
+// part of the same group.
+const ClusterReach float64 = BotDiam * 4
+
+// Cluster is a group of bots close together.
+type Cluster struct {
+	Bots     []*GDBBot
+	Centroid geom.Vec2
+	Health   int     // Total health of the group
+	Spread   float64 // Root mean square distance from the centroid
+}
+
+// Size returns how many bots are in the group.
+func (c Cluster) Size() int {
+	return len(c.Bots)
+}
+
+// Clusters splits bots into groups within reach of each
+// other, biggest first (by size, then health). A bot on its
+// own is a group of one.
+func Clusters(bots []*GDBBot, reach float64) []Cluster {
+	points := make([]geom.Vec2, len(bots))
+	for i, bot := range bots {
+		points[i] = bot.Pos()
+	}
+	labels, n := cluster.DBSCAN(points, reach, 1)
+
+	groups := make([]Cluster, n)
+	members := make([][]geom.Vec2, n)
+	for i, label := range labels {
+		groups[label].Bots = append(groups[label].Bots, bots[i])
+		groups[label].Health += bots[i].Health
+		members[label] = append(members[label], points[i])
+	}
+	for i := range groups {
+		groups[i].Centroid = cluster.Centroid(members[i])
+		groups[i].Spread = cluster.Spread(members[i])
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		if groups[i].Size() != groups[j].Size() {
+			return groups[i].Size() > groups[j].Size()
+		}
+		return groups[i].Health > groups[j].Health
+	})
+	Trace(TraceStrategy, "Found clusters", "bots", len(bots), "clusters", n)
+	return groups
+}
+
+// EnemyClusters groups the enemy's bots with ClusterReach.
+func (gdb *GameDatabase) EnemyClusters() []Cluster {
+	return Clusters(gdb.TheirBots(), ClusterReach)
+}
+
+// NearestCluster returns the group whose centroid is
+// closest to p, and false if there are none.
+func NearestCluster(clusters []Cluster, p geom.Vec2) (Cluster, bool) {
+	if len(clusters) == 0 {
+		return Cluster{}, false
+	}
+	best := clusters[0]
+	for _, c := range clusters[1:] {
+		if c.Centroid.Dist(p) < best.Centroid.Dist(p) {
+			best = c
+		}
+	}
+	return best, true
+}
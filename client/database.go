@@ -0,0 +1,240 @@
+package client
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ScrappersIO/Player-Samples/geom"
+)
+
+///////////////////
+// GAME DATABASE //
+///////////////////
+
+// GameDatabase stores all the data
+// sent to us by the game.
+type GameDatabase struct {
+	Bots []GDBBot
+	PID  int
+
+	hits   *HitMatcher
+	hitLog []Hit // Shots matched to the damage they did, oldest first, see Hits
+}
+
+var (
+	// A copy of the bots for the metrics and debug endpoints,
+	// which run on their own goroutines. processMsgs publishes
+	// it after every message, so it's never caught half way
+	// through an update.
+	snapshotPID  int
+	snapshotBots []GDBBot
+	snapshotMu   sync.Mutex
+)
+
+// publish makes a snapshot of the database for other
+// goroutines to read.
+func (gdb *GameDatabase) publish() {
+	bots := append([]GDBBot{}, gdb.Bots...)
+	snapshotMu.Lock()
+	defer snapshotMu.Unlock()
+	snapshotPID, snapshotBots = gdb.PID, bots
+}
+
+// snapshot returns the last published player ID and bots.
+// The bots are a copy, but shared, so don't change them.
+func snapshot() (pid int, bots []GDBBot) {
+	snapshotMu.Lock()
+	defer snapshotMu.Unlock()
+	return snapshotPID, snapshotBots
+}
+
+// GDBBot is the Bot struct for the Game Database.
+type GDBBot struct {
+	BID, PID   int
+	X, Y       int
+	Health     int
+	Fired      bool
+	HitX, HitY int
+	Scrap      int
+	Shield     bool
+
+	Vel      geom.Vec2 // Units per second, see Velocity
+	VelKnown bool
+	Updated  time.Time
+	HitAt    time.Time // When it last lost health, see HitWithin
+
+	// How an enemy bot seems to be sharing its power.
+	Inferred PowerEstimate
+
+	// Where and when velocity was last measured from.
+	velPos geom.Vec2
+	velAt  time.Time
+
+	// BOT messages since it last fired.
+	sinceShot int
+}
+
+// InserUpdateBot either updates a bot's info,
+// deletes a dead bot, or adds a new bot.
+func (gdb *GameDatabase) InsertUpdateBot(b BotMsg) {
+
+	// Enemies feed the threat map, dead or alive
+	if b.PID != gdb.PID {
+		Threats.observe(b)
+	}
+
+	// If this is a dead bot, remove and ignore
+	if b.Health <= 0 {
+
+		for i := 0; i < len(gdb.Bots); i++ {
+			if gdb.Bots[i].BID == b.BID && gdb.Bots[i].PID == b.PID {
+				Trace(TraceDB, "Removed dead bot", "pid", b.PID, "bid", b.BID)
+				gdb.infer(&gdb.Bots[i], b, time.Now())
+				logEvent("died", b.PID, b.BID, "")
+				if b.PID == gdb.PID {
+					forgetRoute(b.BID)
+				}
+				gdb.Bots = append(gdb.Bots[:i], gdb.Bots[i+1:]...)
+				return
+			}
+		}
+		return
+	}
+
+	// Live bots tell us where the arena is
+	observeArena(b.Pos())
+
+	// Otherwise, update...
+	for i, bot := range gdb.Bots {
+		if b.BID == bot.BID && b.PID == bot.PID {
+			if b.Health < bot.Health {
+				logEvent("damaged", b.PID, b.BID, fmt.Sprintf("health %v -> %v", bot.Health, b.Health))
+				gdb.Bots[i].HitAt = time.Now()
+			}
+			gdb.Bots[i].track(b, time.Now())
+			gdb.infer(&gdb.Bots[i], b, time.Now())
+			gdb.Bots[i].update(b)
+			Trace(TraceDB, "Updated bot", "bot", gdb.Bots[i])
+
+			// Our bots shield up when hit, if the strategy wants
+			if b.PID == gdb.PID {
+				if cmd, ok := shieldObserve(&gdb.Bots[i]); ok {
+					send(cmd)
+				}
+			}
+			return
+		}
+	}
+
+	// ... or Add
+	bot := GDBBot{}
+	bot.PID = b.PID
+	bot.BID = b.BID
+	bot.track(b, time.Now())
+	bot.update(b)
+	gdb.Bots = append(gdb.Bots, bot)
+	Trace(TraceDB, "Added bot", "bot", bot)
+	logEvent("added", b.PID, b.BID, "")
+}
+
+// update copies the state in a BOT message to the bot.
+func (b *GDBBot) update(msg BotMsg) {
+	b.X = msg.X
+	b.Y = msg.Y
+	b.Health = msg.Health
+	b.Fired = msg.Fired
+	b.HitX = msg.HitX
+	b.HitY = msg.HitY
+	b.Scrap = msg.Scrap
+	b.Shield = msg.Shield
+}
+
+// find returns the bot with ref, or nil if it's gone.
+func (gdb *GameDatabase) find(ref BotRef) *GDBBot {
+	for i := range gdb.Bots {
+		if gdb.Bots[i].PID == ref.PID && gdb.Bots[i].BID == ref.BID {
+			return &gdb.Bots[i]
+		}
+	}
+	return nil
+}
+
+// HitWithin reports whether the bot has lost health in the
+// last d.
+func (b *GDBBot) HitWithin(d time.Duration) bool {
+	return !b.HitAt.IsZero() && time.Since(b.HitAt) < d
+}
+
+// MyBots returns a pointer array of GDBBots owned by us.
+func (gdb *GameDatabase) MyBots() []*GDBBot {
+	bots := make([]*GDBBot, 0)
+	for i, bot := range gdb.Bots {
+		if bot.PID == gdb.PID {
+			bots = append(bots, &gdb.Bots[i])
+		}
+	}
+	return bots
+}
+
+// TheirBots returns a pointer array of GDBBots NOT owned by us.
+func (gdb *GameDatabase) TheirBots() []*GDBBot {
+	bots := make([]*GDBBot, 0)
+	for i, bot := range gdb.Bots {
+		if bot.PID != gdb.PID {
+			bots = append(bots, &gdb.Bots[i])
+		}
+	}
+	return bots
+}
+
+// Pos returns where the bot is.
+func (b *GDBBot) Pos() geom.Vec2 {
+	return geom.FromInt(b.X, b.Y)
+}
+
+// HitPos returns where the bot's last shot landed.
+func (b *GDBBot) HitPos() geom.Vec2 {
+	return geom.FromInt(b.HitX, b.HitY)
+}
+
+// Move returns a command struct for movement. This is
+// where the destination becomes game coordinates.
+func (b *GDBBot) Move(dest geom.Vec2) Command {
+	cmd := Command{}
+	cmd.Cmd = "MOVE"
+	cmd.BID = b.BID
+	cmd.X, cmd.Y = dest.Int()
+	return cmd
+}
+
+// Follow is a convenience function which returns a
+// command stuct for movement using a bot as a destination.
+func (b *GDBBot) Follow(bot *GDBBot) Command {
+
+	// We want to follow at a respectable distance,
+	// so we stop a bot's width short.
+	angle := bot.Pos().AngleTo(b.Pos())
+	return b.Move(bot.Pos().Add(geom.Polar(BotDiam, angle)))
+}
+
+// Target returns a command struct for targeting a bot.
+func (b *GDBBot) Target(bot *GDBBot) Command {
+	cmd := Command{}
+	cmd.Cmd = "TARGET"
+	cmd.BID = b.BID
+	cmd.TPID = bot.PID
+	cmd.TBID = bot.BID
+	return cmd
+}
+
+// Power returns a command struct for seting the power of a bot.
+func (b *GDBBot) Power(fire, move, shield int) Command {
+	cmd := Command{}
+	cmd.Cmd = "POWER"
+	cmd.BID = b.BID
+	cmd.FPow = fire
+	cmd.MPow = move
+	cmd.SPow = shield
+	return cmd
+}
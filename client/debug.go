@@ -0,0 +1,164 @@
+package client
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/pprof"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ScrappersIO/Player-Samples/geom"
+)
+
+// How many events the debug endpoint remembers.
+const maxEvents = 200
+
+// Event is something notable that happened during the match.
+type Event struct {
+	Time     time.Time
+	Kind     string
+	PID, BID int
+	Detail   string `json:",omitempty"`
+}
+
+// BotRef identifies a bot without holding on to it.
+type BotRef struct {
+	PID, BID int
+}
+
+// DebugState is what the debug endpoint serves.
+type DebugState struct {
+	PID          int
+	Bots         []GDBBot
+	Events       []Event
+	LastCommands map[int][]Command // By BID
+	Phase        string
+	Targets      map[int]BotRef // By BID
+	Arena        *geom.Rect     // Nil until we know anything
+	Weapons      WeaponStats
+}
+
+var (
+	// Recent events, oldest first.
+	events   []Event
+	eventsMu sync.Mutex
+
+	// What the strategy told us it is up to.
+	phase    string
+	targets  = make(map[int]BotRef)
+	statusMu sync.Mutex
+)
+
+// logEvent remembers an event for the debug endpoint.
+func logEvent(kind string, pid, bid int, detail string) {
+	eventsMu.Lock()
+	defer eventsMu.Unlock()
+	events = append(events, Event{time.Now(), kind, pid, bid, detail})
+	if len(events) > maxEvents {
+		events = events[len(events)-maxEvents:]
+	}
+}
+
+// SetPhase records what the strategy is currently doing,
+// so it shows up on the debug endpoint.
+func SetPhase(p string) {
+	statusMu.Lock()
+	defer statusMu.Unlock()
+	if p != phase {
+		Trace(TraceStrategy, "Phase changed", "from", phase, "to", p)
+		logEvent("phase", GDB.PID, 0, p)
+	}
+	phase = p
+}
+
+// SetTarget records which bot one of our bots is going
+// after, so it shows up on the debug endpoint.
+func SetTarget(bot, target *GDBBot) {
+	statusMu.Lock()
+	defer statusMu.Unlock()
+	targets[bot.BID] = BotRef{target.PID, target.BID}
+}
+
+// targetedBy counts how many of our bots SetTarget has
+// pointed at target.
+func targetedBy(target BotRef) int {
+	statusMu.Lock()
+	defer statusMu.Unlock()
+	n := 0
+	for _, ref := range targets {
+		if ref == target {
+			n++
+		}
+	}
+	return n
+}
+
+// serveDebug starts an HTTP listener on addr serving the
+// game state at /debug/state and Go's pprof handlers at
+// /debug/pprof/.
+func serveDebug(addr string) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/state", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		enc.Encode(debugState())
+	})
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+
+	go func() {
+		err := http.ListenAndServe(addr, mux)
+		if err != nil {
+			slog.Error("Debug listener stopped", "addr", addr, "err", err)
+		}
+	}()
+}
+
+// debugState gathers a copy of everything we know.
+func debugState() DebugState {
+	state := DebugState{}
+
+	// The bots from the last snapshot, as the database is busy
+	// on another goroutine
+	pid, bots := snapshot()
+	state.PID = pid
+	state.Bots = append([]GDBBot{}, bots...)
+	state.Weapons = Weapons.Stats()
+
+	eventsMu.Lock()
+	state.Events = append([]Event{}, events...)
+	eventsMu.Unlock()
+
+	// Commands grouped by bot, in a stable order
+	state.LastCommands = make(map[int][]Command)
+	lastSentMu.Lock()
+	for key, cmd := range lastSent {
+		state.LastCommands[key.BID] = append(state.LastCommands[key.BID], cmd)
+	}
+	lastSentMu.Unlock()
+	for _, cmds := range state.LastCommands {
+		sort.Slice(cmds, func(i, j int) bool {
+			return cmds[i].Cmd < cmds[j].Cmd
+		})
+	}
+
+	statusMu.Lock()
+	state.Phase = phase
+	state.Targets = make(map[int]BotRef)
+	for bid, target := range targets {
+		state.Targets[bid] = target
+	}
+	statusMu.Unlock()
+
+	if walls, ok := Arena(); ok {
+		state.Arena = &walls
+	}
+
+	return state
+}
@@ -0,0 +1,228 @@
+package client
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ScrappersIO/Player-Samples/geom"
+)
+
+// HurtWindow is how far back Hurting looks for damage an
+// enemy has done to us.
+const HurtWindow = 5 * time.Second
+
+// Hit is a shot matched to the damage it did.
+type Hit struct {
+	Time    time.Time // When the victim lost health
+	Shooter BotRef
+	Victim  BotRef
+	Damage  int
+	Fatal   bool    // The victim died, so Damage may be less than the shot could do
+	Dist    float64 // How far the shot travelled
+}
+
+// HitMatcher pairs up shots with the health drops they
+// caused. The game tells us where each shot landed and,
+// separately, when a bot loses health; a shot and a drop
+// within Window of each other and Reach of each other are
+// the same hit. Whichever arrives second completes it.
+type HitMatcher struct {
+	Window time.Duration
+	Reach  float64
+
+	shots []*sightedShot
+	drops []*healthDrop
+}
+
+// sightedShot is a shot we've seen land somewhere.
+type sightedShot struct {
+	at      time.Time
+	shooter BotRef
+	from    geom.Vec2
+	pos     geom.Vec2
+}
+
+// healthDrop is a bot we've seen lose health.
+type healthDrop struct {
+	at     time.Time
+	victim BotRef
+	pos    geom.Vec2
+	amount int
+	fatal  bool
+}
+
+// NewHitMatcher returns a HitMatcher allowing half a second
+// and a bot's width between a shot and its damage.
+func NewHitMatcher() *HitMatcher {
+	return &HitMatcher{Window: 500 * time.Millisecond, Reach: BotDiam}
+}
+
+// Shot notes shooter's shot from from landing at pos,
+// returning the hit if it explains a health drop we've
+// already seen.
+func (m *HitMatcher) Shot(t time.Time, shooter BotRef, from, pos geom.Vec2) (Hit, bool) {
+	s := &sightedShot{t, shooter, from, pos}
+	m.prune(t)
+	best := -1
+	for i, d := range m.drops {
+		if m.matches(s, d) && (best < 0 || d.pos.Dist(pos) < m.drops[best].pos.Dist(pos)) {
+			best = i
+		}
+	}
+	if best < 0 {
+		m.shots = append(m.shots, s)
+		return Hit{}, false
+	}
+	d := m.drops[best]
+	m.drops = append(m.drops[:best], m.drops[best+1:]...)
+	return Hit{d.at, shooter, d.victim, d.amount, d.fatal, from.Dist(pos)}, true
+}
+
+// Drop notes victim at pos losing amount of health,
+// returning the hit if a shot we've already seen explains
+// it.
+func (m *HitMatcher) Drop(t time.Time, victim BotRef, pos geom.Vec2, amount int, fatal bool) (Hit, bool) {
+	d := &healthDrop{t, victim, pos, amount, fatal}
+	m.prune(t)
+	best := -1
+	for i, s := range m.shots {
+		if m.matches(s, d) && (best < 0 || s.pos.Dist(pos) < m.shots[best].pos.Dist(pos)) {
+			best = i
+		}
+	}
+	if best < 0 {
+		m.drops = append(m.drops, d)
+		return Hit{}, false
+	}
+	s := m.shots[best]
+	m.shots = append(m.shots[:best], m.shots[best+1:]...)
+	return Hit{t, s.shooter, victim, amount, fatal, s.from.Dist(s.pos)}, true
+}
+
+// matches reports whether the shot could have caused the
+// drop. Nobody shoots their own side.
+func (m *HitMatcher) matches(s *sightedShot, d *healthDrop) bool {
+	dt := s.at.Sub(d.at)
+	if dt < 0 {
+		dt = -dt
+	}
+	return dt <= m.Window && s.pos.Dist(d.pos) <= m.Reach && s.shooter.PID != d.victim.PID
+}
+
+// prune forgets shots and drops too old to match anything
+// from now on.
+func (m *HitMatcher) prune(now time.Time) {
+	shots := m.shots[:0]
+	for _, s := range m.shots {
+		if now.Sub(s.at) <= m.Window {
+			shots = append(shots, s)
+		}
+	}
+	m.shots = shots
+	drops := m.drops[:0]
+	for _, d := range m.drops {
+		if now.Sub(d.at) <= m.Window {
+			drops = append(drops, d)
+		}
+	}
+	m.drops = drops
+}
+
+// hitsMu guards the database's hits. processMsgs records
+// them, but strategies read them from their own goroutine.
+var hitsMu sync.Mutex
+
+// recordHit adds a hit to the database, keeping them in
+// time order. A hit matched late takes the time of its
+// health drop, which may be before hits we already have.
+func (gdb *GameDatabase) recordHit(h Hit) {
+	hitsMu.Lock()
+	i := sort.Search(len(gdb.hitLog), func(i int) bool {
+		return gdb.hitLog[i].Time.After(h.Time)
+	})
+	gdb.hitLog = append(gdb.hitLog, Hit{})
+	copy(gdb.hitLog[i+1:], gdb.hitLog[i:])
+	gdb.hitLog[i] = h
+	hitsMu.Unlock()
+	Trace(TraceDB, "Matched hit", "shooter", h.Shooter, "victim", h.Victim, "damage", h.Damage, "fatal", h.Fatal)
+	if h.Fatal {
+		logEvent("kill", h.Shooter.PID, h.Shooter.BID, fmt.Sprintf("victim %v", h.Victim))
+	}
+}
+
+// Credit is what a bot or player has done to the other
+// side, going by the hits we've matched.
+type Credit struct {
+	Damage, Hits, Kills int
+}
+
+// add counts a hit towards the credit.
+func (c *Credit) add(h Hit) {
+	c.Damage += h.Damage
+	c.Hits++
+	if h.Fatal {
+		c.Kills++
+	}
+}
+
+// Hits returns a copy of the shots we've matched to the
+// damage they did, oldest first.
+func (gdb *GameDatabase) Hits() []Hit {
+	hitsMu.Lock()
+	defer hitsMu.Unlock()
+	return append([]Hit{}, gdb.hitLog...)
+}
+
+// CreditByBot totals every bot's hits.
+func (gdb *GameDatabase) CreditByBot() map[BotRef]Credit {
+	credit := make(map[BotRef]Credit)
+	for _, h := range gdb.Hits() {
+		c := credit[h.Shooter]
+		c.add(h)
+		credit[h.Shooter] = c
+	}
+	return credit
+}
+
+// CreditByPlayer totals every player's hits.
+func (gdb *GameDatabase) CreditByPlayer() map[int]Credit {
+	credit := make(map[int]Credit)
+	for _, h := range gdb.Hits() {
+		c := credit[h.Shooter.PID]
+		c.add(h)
+		credit[h.Shooter.PID] = c
+	}
+	return credit
+}
+
+// DamageBy returns how much damage shooter has done
+// since the given time.
+func (gdb *GameDatabase) DamageBy(shooter BotRef, since time.Time) int {
+	hitsMu.Lock()
+	defer hitsMu.Unlock()
+	total := 0
+	for i := len(gdb.hitLog) - 1; i >= 0 && !gdb.hitLog[i].Time.Before(since); i-- {
+		if gdb.hitLog[i].Shooter == shooter {
+			total += gdb.hitLog[i].Damage
+		}
+	}
+	return total
+}
+
+// logCredit logs each player's hits at the end of the game.
+func (gdb *GameDatabase) logCredit() {
+	credit := gdb.CreditByPlayer()
+	pids := make([]int, 0, len(credit))
+	for pid := range credit {
+		pids = append(pids, pid)
+	}
+	sort.Ints(pids)
+	for _, pid := range pids {
+		c := credit[pid]
+		slog.Info("Match stats", "pid", pid, "us", pid == gdb.PID,
+			"damage", c.Damage, "hits", c.Hits, "kills", c.Kills)
+	}
+}
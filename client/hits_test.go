@@ -0,0 +1,132 @@
+package client
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ScrappersIO/Player-Samples/geom"
+)
+
+func TestHitMatcher(t *testing.T) {
+	t0 := time.Unix(1000, 0)
+	ms := func(n int) time.Time { return t0.Add(time.Duration(n) * time.Millisecond) }
+	us, them, other := BotRef{1, 1}, BotRef{2, 1}, BotRef{2, 2}
+
+	// An event is a shot from the origin landing (victim
+	// zero) or a health drop (shooter zero).
+	type event struct {
+		at      time.Time
+		shooter BotRef
+		victim  BotRef
+		pos     geom.Vec2
+		amount  int
+	}
+	tests := []struct {
+		name   string
+		events []event
+		want   []Hit
+	}{
+		{"shot then drop", []event{
+			{at: ms(0), shooter: us, pos: geom.V(30, 40)},
+			{at: ms(100), victim: them, pos: geom.V(32, 40), amount: 3},
+		}, []Hit{{Time: ms(100), Shooter: us, Victim: them, Damage: 3, Dist: 50}}},
+		{"drop then shot", []event{
+			{at: ms(0), victim: them, pos: geom.V(30, 40), amount: 3},
+			{at: ms(100), shooter: us, pos: geom.V(32, 40)},
+		}, []Hit{{Time: ms(0), Shooter: us, Victim: them, Damage: 3, Dist: geom.V(32, 40).Len()}}},
+		{"too late", []event{
+			{at: ms(0), shooter: us, pos: geom.V(10, 10)},
+			{at: ms(600), victim: them, pos: geom.V(10, 10), amount: 3},
+		}, nil},
+		{"too far", []event{
+			{at: ms(0), shooter: us, pos: geom.V(10, 10)},
+			{at: ms(100), victim: them, pos: geom.V(10+BotDiam+1, 10), amount: 3},
+		}, nil},
+		{"own side", []event{
+			{at: ms(0), shooter: us, pos: geom.V(10, 10)},
+			{at: ms(100), victim: BotRef{1, 2}, pos: geom.V(10, 10), amount: 3},
+		}, nil},
+		{"nearest drop", []event{
+			{at: ms(0), victim: other, pos: geom.V(10+BotDiam, 10), amount: 2},
+			{at: ms(0), victim: them, pos: geom.V(11, 10), amount: 3},
+			{at: ms(50), shooter: us, pos: geom.V(10, 10)},
+		}, []Hit{{Time: ms(0), Shooter: us, Victim: them, Damage: 3, Dist: geom.V(10, 10).Len()}}},
+		{"nearest shot", []event{
+			{at: ms(0), shooter: BotRef{1, 2}, pos: geom.V(10+BotDiam, 10)},
+			{at: ms(0), shooter: us, pos: geom.V(11, 10)},
+			{at: ms(50), victim: them, pos: geom.V(10, 10), amount: 3},
+		}, []Hit{{Time: ms(50), Shooter: us, Victim: them, Damage: 3, Dist: geom.V(11, 10).Len()}}},
+		{"each shot used once", []event{
+			{at: ms(0), shooter: us, pos: geom.V(10, 10)},
+			{at: ms(50), victim: them, pos: geom.V(10, 10), amount: 3},
+			{at: ms(60), victim: other, pos: geom.V(10, 10), amount: 2},
+		}, []Hit{{Time: ms(50), Shooter: us, Victim: them, Damage: 3, Dist: geom.V(10, 10).Len()}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewHitMatcher()
+			var got []Hit
+			for _, e := range tt.events {
+				var h Hit
+				var ok bool
+				if e.victim == (BotRef{}) {
+					h, ok = m.Shot(e.at, e.shooter, geom.V(0, 0), e.pos)
+				} else {
+					h, ok = m.Drop(e.at, e.victim, e.pos, e.amount, false)
+				}
+				if ok {
+					got = append(got, h)
+				}
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got hits %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("hit %d is %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRecordHit(t *testing.T) {
+	t0 := time.Unix(1000, 0)
+	us, them := BotRef{1, 1}, BotRef{2, 1}
+	var gdb GameDatabase
+
+	// The second is matched late, so arrives out of order
+	for _, h := range []Hit{
+		{Time: t0, Shooter: us, Victim: them, Damage: 1},
+		{Time: t0.Add(2 * time.Second), Shooter: us, Victim: them, Damage: 2},
+		{Time: t0.Add(time.Second), Shooter: them, Victim: us, Damage: 4},
+		{Time: t0.Add(3 * time.Second), Shooter: us, Victim: them, Damage: 8, Fatal: true},
+	} {
+		gdb.recordHit(h)
+	}
+	hits := gdb.Hits()
+	for i := 1; i < len(hits); i++ {
+		if hits[i].Time.Before(hits[i-1].Time) {
+			t.Errorf("hits out of order: %v", hits)
+		}
+	}
+
+	tests := []struct {
+		shooter BotRef
+		since   time.Time
+		want    int
+	}{
+		{us, t0, 11},
+		{us, t0.Add(time.Second), 10},
+		{us, t0.Add(4 * time.Second), 0},
+		{them, t0, 4},
+	}
+	for _, tt := range tests {
+		if got := gdb.DamageBy(tt.shooter, tt.since); got != tt.want {
+			t.Errorf("DamageBy(%v, %v) = %d, want %d", tt.shooter, tt.since.Sub(t0), got, tt.want)
+		}
+	}
+	if got, want := gdb.CreditByPlayer()[1], (Credit{Damage: 11, Hits: 3, Kills: 1}); got != want {
+		t.Errorf("our credit is %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,173 @@
+package client
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	// How much each piece of evidence counts towards an
+	// enemy's inferred power.
+	inferSmoothing = 0.3
+	// A bot that goes this many BOT messages without firing
+	// probably has no FPow.
+	quietShots = 20
+)
+
+// PowerEstimate is our best guess at how an enemy bot is
+// sharing its power, from how it moves, shoots and takes
+// damage. Each part is only meaningful if it's Known.
+type PowerEstimate struct {
+	F, M, S                float64
+	FKnown, MKnown, SKnown bool
+}
+
+// Split returns the estimate as a whole split adding up to
+// MaxPow, sharing out whatever isn't accounted for evenly
+// among the parts we know nothing about.
+func (e PowerEstimate) Split() Split {
+	known := [3]bool{e.FKnown, e.MKnown, e.SKnown}
+	parts := [3]float64{e.F, e.M, e.S}
+	used, unknown := 0.0, 0
+	for i := range parts {
+		if known[i] {
+			used += parts[i]
+		} else {
+			unknown++
+		}
+	}
+	for i := range parts {
+		if !known[i] {
+			parts[i] = math.Max(float64(MaxPow)-used, 0) / float64(unknown)
+		}
+	}
+	return SplitPower(parts[0], parts[1], parts[2])
+}
+
+// shieldFactor is how much of a shot gets through to a bot
+// with the given SPow, assuming a full shield halves it.
+func shieldFactor(spow float64) float64 {
+	return 1 - spow/(float64(MaxPow)*shieldedToughness)
+}
+
+// infer updates the bot's inferred power from a BOT message
+// that's about to update it, including anything we learn by
+// matching shots to the damage they did.
+func (gdb *GameDatabase) infer(b *GDBBot, msg BotMsg, now time.Time) {
+	if msg.PID != gdb.PID {
+		b.inferMove()
+		b.inferFire(msg)
+
+		// A shield that's down is no SPow, but once it's back
+		// up we have to learn how strong it is all over again.
+		switch {
+		case !msg.Shield:
+			b.Inferred.S, b.Inferred.SKnown = 0, true
+		case !b.Shield || b.Inferred.S == 0:
+			b.Inferred.S, b.Inferred.SKnown = 0, false
+		}
+	}
+
+	// Match shots to the damage they did
+	if gdb.hits == nil {
+		gdb.hits = NewHitMatcher()
+	}
+	if msg.Fired {
+		Weapons.observeShot(msg.Pos().Dist(msg.HitPos()))
+		if h, ok := gdb.hits.Shot(now, BotRef{msg.PID, msg.BID}, msg.Pos(), msg.HitPos()); ok {
+			gdb.hit(h)
+		}
+	}
+	if msg.Health < b.Health {
+		amount, fatal := b.Health-max(msg.Health, 0), msg.Health <= 0
+		if h, ok := gdb.hits.Drop(now, BotRef{msg.PID, msg.BID}, msg.Pos(), amount, fatal); ok {
+			gdb.hit(h)
+		}
+	}
+}
+
+// inferMove guesses MPow from how fast the bot is going.
+// A bot that's stopped may just have arrived, so that tells
+// us nothing.
+func (b *GDBBot) inferMove() {
+	vel, ok := b.Velocity()
+	if !ok || vel.Len() < 1 {
+		return
+	}
+	mpow := math.Min(vel.Len()/SpeedPerMPow(), float64(MaxPow))
+	b.Inferred.M = smooth(b.Inferred.M, mpow, b.Inferred.MKnown)
+	b.Inferred.MKnown = true
+}
+
+// inferFire notes a bot that never shoots has no FPow.
+func (b *GDBBot) inferFire(msg BotMsg) {
+	if msg.Fired {
+		b.sinceShot = 0
+		return
+	}
+	b.sinceShot++
+	if b.sinceShot >= quietShots {
+		b.Inferred.F, b.Inferred.FKnown = 0, true
+	}
+}
+
+// hit records a hit and learns what it can about power
+// and weapons from it.
+func (gdb *GameDatabase) hit(h Hit) {
+	gdb.recordHit(h)
+	lastSentMu.Lock()
+	shooterPower, shooterPowered := lastSent[sentKey{"POWER", h.Shooter.BID}]
+	victimPower, victimPowered := lastSent[sentKey{"POWER", h.Victim.BID}]
+	lastSentMu.Unlock()
+
+	// Our shots at a bot with its shield down show what our
+	// FPow does. Anything else depends on a guess at someone's
+	// power, which is what the weapon model is for working out.
+	fpow := -1
+	if h.Shooter.PID == gdb.PID && shooterPowered {
+		if victim := gdb.find(h.Victim); victim != nil && !victim.Shield {
+			fpow = shooterPower.FPow
+		}
+	}
+	Weapons.observeHit(h, fpow)
+	if h.Fatal {
+		return
+	}
+
+	switch {
+
+	// They hit us: knowing our shield, the damage tells us
+	// their FPow.
+	case h.Shooter.PID != gdb.PID && h.Victim.PID == gdb.PID && victimPowered:
+		shooter := gdb.find(h.Shooter)
+		if shooter == nil {
+			return
+		}
+		fpow := float64(h.Damage) / (Weapons.PerFPow() * shieldFactor(float64(victimPower.SPow)))
+		fpow = math.Min(fpow, float64(MaxPow))
+		shooter.Inferred.F = smooth(shooter.Inferred.F, fpow, shooter.Inferred.FKnown)
+		shooter.Inferred.FKnown = true
+
+	// We hit them with their shield up: knowing our FPow, the
+	// damage tells us how strong it is.
+	case h.Shooter.PID == gdb.PID && h.Victim.PID != gdb.PID && shooterPowered && shooterPower.FPow > 0:
+		victim := gdb.find(h.Victim)
+		if victim == nil || !victim.Shield {
+			return
+		}
+		through := float64(h.Damage) / Weapons.Damage(shooterPower.FPow)
+		spow := (1 - through) * float64(MaxPow) * shieldedToughness
+		spow = math.Min(math.Max(spow, 0), float64(MaxPow))
+		victim.Inferred.S = smooth(victim.Inferred.S, spow, victim.Inferred.SKnown)
+		victim.Inferred.SKnown = true
+	}
+}
+
+// smooth folds a new measurement into an estimate, or
+// starts one.
+func smooth(old, measured float64, known bool) float64 {
+	if !known {
+		return measured
+	}
+	return old + (measured-old)*inferSmoothing
+}
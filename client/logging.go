@@ -0,0 +1,135 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// Subsystem names a part of the client that can be traced
+// on its own with the -trace flag.
+type Subsystem string
+
+const (
+	TraceNet      Subsystem = "net"      // Raw lines to and from the game.
+	TraceMsg      Subsystem = "msg"      // Decoded game messages.
+	TraceDB       Subsystem = "db"       // Changes to the game database.
+	TraceStrategy Subsystem = "strategy" // Decisions made by the strategy.
+	TraceCmd      Subsystem = "cmd"      // Commands sent to the game.
+)
+
+// Subsystems lists every subsystem that can be traced.
+var Subsystems = []Subsystem{TraceNet, TraceMsg, TraceDB, TraceStrategy, TraceCmd}
+
+// LevelTrace is the level trace lines are logged at. They
+// are shown whenever their subsystem is switched on, no
+// matter what the log level is.
+const LevelTrace = slog.LevelDebug - 4
+
+// Subsystems switched on with -trace.
+var tracing = make(map[Subsystem]bool)
+
+// setupLogging installs the default slog logger. level is
+// one of debug, info, warn or error, format is text or json
+// and trace is a comma separated list of subsystems (or
+// "all") to trace.
+func setupLogging(out io.Writer, level, format, trace string) error {
+
+	var minLevel slog.Level
+	err := minLevel.UnmarshalText([]byte(level))
+	if err != nil {
+		return fmt.Errorf("bad log level %q", level)
+	}
+
+	// Which subsystems are we tracing?
+	for _, name := range strings.Split(trace, ",") {
+		name = strings.TrimSpace(name)
+		switch {
+		case name == "":
+		case name == "all":
+			for _, s := range Subsystems {
+				tracing[s] = true
+			}
+		case isSubsystem(Subsystem(name)):
+			tracing[Subsystem(name)] = true
+		default:
+			return fmt.Errorf("unknown trace subsystem %q", name)
+		}
+	}
+
+	// Name our custom level in the output
+	opts := &slog.HandlerOptions{}
+	opts.Level = LevelTrace
+	opts.ReplaceAttr = func(groups []string, a slog.Attr) slog.Attr {
+		if a.Key == slog.LevelKey && a.Value.Any() == LevelTrace {
+			a.Value = slog.StringValue("TRACE")
+		}
+		return a
+	}
+
+	var handler slog.Handler
+	switch format {
+	case "text":
+		handler = slog.NewTextHandler(out, opts)
+	case "json":
+		handler = slog.NewJSONHandler(out, opts)
+	default:
+		return fmt.Errorf("bad log format %q", format)
+	}
+
+	slog.SetDefault(slog.New(levelHandler{handler, minLevel}))
+	return nil
+}
+
+// isSubsystem reports whether s is a known subsystem.
+func isSubsystem(s Subsystem) bool {
+	for _, known := range Subsystems {
+		if s == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Tracing reports whether trace lines for s are logged.
+// Check it before building anything expensive to trace.
+func Tracing(s Subsystem) bool {
+	return tracing[s]
+}
+
+// Trace logs msg for subsystem s if it is being traced.
+func Trace(s Subsystem, msg string, args ...any) {
+	if !tracing[s] {
+		return
+	}
+	args = append([]any{"subsystem", s}, args...)
+	slog.Log(context.Background(), LevelTrace, msg, args...)
+}
+
+// fatal logs an error and exits, like log.Fatal does.
+func fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
+}
+
+// levelHandler drops records below min, except for trace
+// records which were already filtered by subsystem.
+type levelHandler struct {
+	slog.Handler
+	min slog.Level
+}
+
+func (h levelHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level == LevelTrace || level >= h.min
+}
+
+func (h levelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return levelHandler{h.Handler.WithAttrs(attrs), h.min}
+}
+
+func (h levelHandler) WithGroup(name string) slog.Handler {
+	return levelHandler{h.Handler.WithGroup(name), h.min}
+}
@@ -0,0 +1,137 @@
+package client
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"sort"
+	"sync"
+)
+
+// metricSet counts what the client has been up to so a
+// local Prometheus (or curl) can scrape it during a match.
+type metricSet struct {
+	mu           sync.Mutex
+	received     map[string]int64
+	decodeErrors int64
+	sent         map[string]int64
+	repeated     int64
+}
+
+var metrics = metricSet{
+	received: make(map[string]int64),
+	sent:     make(map[string]int64),
+}
+
+// countReceived counts a message of msgType from the game.
+func (m *metricSet) countReceived(msgType string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.received[msgType]++
+}
+
+// countDecodeError counts a message we couldn't unmarshal.
+func (m *metricSet) countDecodeError() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.decodeErrors++
+}
+
+// countSent counts a command sent to the game.
+func (m *metricSet) countSent(cmd string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sent[cmd]++
+}
+
+// countRepeated counts a command sent that repeated the
+// last one of its kind for the bot.
+func (m *metricSet) countRepeated() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.repeated++
+}
+
+// serveMetrics starts an HTTP listener on addr serving
+// the metrics at /metrics.
+func serveMetrics(addr string) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		metrics.write(w)
+	})
+
+	go func() {
+		err := http.ListenAndServe(addr, mux)
+		if err != nil {
+			slog.Error("Metrics listener stopped", "addr", addr, "err", err)
+		}
+	}()
+}
+
+// write writes the metrics in the Prometheus text format.
+func (m *metricSet) write(w io.Writer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	fmt.Fprintln(w, "# HELP scrappers_messages_received_total Messages received from the game by type.")
+	fmt.Fprintln(w, "# TYPE scrappers_messages_received_total counter")
+	for _, msgType := range sortedKeys(m.received) {
+		fmt.Fprintf(w, "scrappers_messages_received_total{type=%q} %v\n", msgType, m.received[msgType])
+	}
+
+	fmt.Fprintln(w, "# HELP scrappers_decode_errors_total Messages from the game that failed to unmarshal.")
+	fmt.Fprintln(w, "# TYPE scrappers_decode_errors_total counter")
+	fmt.Fprintf(w, "scrappers_decode_errors_total %v\n", m.decodeErrors)
+
+	fmt.Fprintln(w, "# HELP scrappers_commands_sent_total Commands sent to the game by kind.")
+	fmt.Fprintln(w, "# TYPE scrappers_commands_sent_total counter")
+	for _, cmd := range sortedKeys(m.sent) {
+		fmt.Fprintf(w, "scrappers_commands_sent_total{cmd=%q} %v\n", cmd, m.sent[cmd])
+	}
+
+	fmt.Fprintln(w, "# HELP scrappers_commands_repeated_total Commands sent that repeated the last one of their kind for the bot.")
+	fmt.Fprintln(w, "# TYPE scrappers_commands_repeated_total counter")
+	fmt.Fprintf(w, "scrappers_commands_repeated_total %v\n", m.repeated)
+
+	fmt.Fprintln(w, "# HELP scrappers_queue_depth Messages waiting on the incoming message queue.")
+	fmt.Fprintln(w, "# TYPE scrappers_queue_depth gauge")
+	fmt.Fprintf(w, "scrappers_queue_depth %v\n", len(msgQueue))
+
+	// Bot counts and health, ours and theirs, from the last
+	// snapshot as the database is busy on another goroutine
+	pid, bots := snapshot()
+	myCount, theirCount := 0, 0
+	myHealth, theirHealth := 0, 0
+	for _, bot := range bots {
+		if bot.PID == pid {
+			myCount++
+			myHealth += bot.Health
+		} else {
+			theirCount++
+			theirHealth += bot.Health
+		}
+	}
+
+	fmt.Fprintln(w, "# HELP scrappers_bots Bots alive.")
+	fmt.Fprintln(w, "# TYPE scrappers_bots gauge")
+	fmt.Fprintf(w, "scrappers_bots{owner=\"mine\"} %v\n", myCount)
+	fmt.Fprintf(w, "scrappers_bots{owner=\"theirs\"} %v\n", theirCount)
+
+	fmt.Fprintln(w, "# HELP scrappers_bot_health Total health of the bots alive.")
+	fmt.Fprintln(w, "# TYPE scrappers_bot_health gauge")
+	fmt.Fprintf(w, "scrappers_bot_health{owner=\"mine\"} %v\n", myHealth)
+	fmt.Fprintf(w, "scrappers_bot_health{owner=\"theirs\"} %v\n", theirHealth)
+}
+
+// sortedKeys returns the keys of counts in order, so the
+// output doesn't shuffle between scrapes.
+func sortedKeys(counts map[string]int64) []string {
+	keys := make([]string, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,150 @@
+package client
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ScrappersIO/Player-Samples/geom"
+)
+
+const (
+	// DefaultSpeedPerMPow is our guess at how many units per
+	// second each point of MPow is worth, until we've watched
+	// our own bots move.
+	DefaultSpeedPerMPow float64 = 25
+	// MaxLead is the furthest ahead, in seconds, we'll predict
+	// where a bot is going. Beyond that it'll have changed its
+	// mind anyway.
+	MaxLead float64 = 3
+)
+
+const (
+	// Messages closer together than this are too close to
+	// measure a velocity between.
+	minVelInterval = 50 * time.Millisecond
+	// A bot we haven't heard from in this long has stopped.
+	velStale = 500 * time.Millisecond
+	// How much each new measurement counts towards velocity.
+	velSmoothing = 0.5
+)
+
+var (
+	// Learned speed per point of MPow.
+	speedPerMPow = DefaultSpeedPerMPow
+	speedMu      sync.Mutex
+)
+
+// track updates the bot's velocity from a message that
+// is about to update its position.
+func (b *GDBBot) track(msg BotMsg, now time.Time) {
+	b.Updated = now
+	if b.velAt.IsZero() {
+		b.velPos, b.velAt = msg.Pos(), now
+		return
+	}
+
+	dt := now.Sub(b.velAt)
+	if dt < minVelInterval {
+		return
+	}
+	vel := msg.Pos().Sub(b.velPos).Scale(1 / dt.Seconds())
+	if b.VelKnown {
+		vel = b.Vel.Lerp(vel, velSmoothing)
+	}
+	b.Vel, b.VelKnown = vel, true
+	b.velPos, b.velAt = msg.Pos(), now
+
+	// Our own bots tell us how fast MPow makes us go
+	if b.PID == GDB.PID {
+		learnSpeed(b.BID, vel.Len(), msg.Pos())
+	}
+}
+
+// learnSpeed refines speedPerMPow from one of our bots
+// moving at speed, as long as it was actually trying to
+// get somewhere and not slowing down on arrival.
+func learnSpeed(bid int, speed float64, pos geom.Vec2) {
+	lastSentMu.Lock()
+	power, powered := lastSent[sentKey{"POWER", bid}]
+	move, moving := lastSent[sentKey{"MOVE", bid}]
+	lastSentMu.Unlock()
+
+	if !powered || !moving || power.MPow <= 0 || speed < 1 {
+		return
+	}
+	if pos.Dist(geom.FromInt(move.X, move.Y)) < BotDiam {
+		return
+	}
+
+	speedMu.Lock()
+	defer speedMu.Unlock()
+	speedPerMPow += (speed/float64(power.MPow) - speedPerMPow) * velSmoothing / 4
+}
+
+// SpeedPerMPow returns how many units per second each point
+// of MPow is worth, as measured so far.
+func SpeedPerMPow() float64 {
+	speedMu.Lock()
+	defer speedMu.Unlock()
+	return speedPerMPow
+}
+
+// Velocity returns the bot's velocity in units per second
+// and whether we've seen enough of it to tell. A bot we
+// haven't heard from in a while is assumed to have stopped.
+func (b *GDBBot) Velocity() (geom.Vec2, bool) {
+	if !b.VelKnown {
+		return geom.Vec2{}, false
+	}
+	if time.Since(b.Updated) > velStale {
+		return geom.Vec2{}, true
+	}
+	return b.Vel, true
+}
+
+// Speed returns how fast one of our bots moves with the
+// MPow we last gave it, or zero if we never powered it.
+func (b *GDBBot) Speed() float64 {
+	lastSentMu.Lock()
+	power, ok := lastSent[sentKey{"POWER", b.BID}]
+	lastSentMu.Unlock()
+	if !ok {
+		return 0
+	}
+	return SpeedPerMPow() * float64(power.MPow)
+}
+
+// Intercept returns where our bot should head to meet
+// target, given how target is moving and how fast we can
+// go. If we can't tell (or can't catch it) that's simply
+// where target is now.
+func (b *GDBBot) Intercept(target *GDBBot) geom.Vec2 {
+	return b.lead(target, b.Speed())
+}
+
+// LeadAim returns where to aim at target for a shot
+// travelling at shotSpeed units per second. A shotSpeed of
+// zero means shots land instantly, so aim right at it.
+func (b *GDBBot) LeadAim(target *GDBBot, shotSpeed float64) geom.Vec2 {
+	return b.lead(target, shotSpeed)
+}
+
+// lead predicts where something leaving our bot at speed
+// meets target, falling back to where target is now.
+func (b *GDBBot) lead(target *GDBBot, speed float64) geom.Vec2 {
+	vel, ok := target.Velocity()
+	if !ok || speed <= 0 {
+		return target.Pos()
+	}
+	meet, t, ok := geom.Intercept(b.Pos(), speed, target.Pos(), vel)
+	if !ok || t > MaxLead {
+		return target.Pos()
+	}
+	return meet
+}
+
+// FollowIntercept returns a command struct for movement
+// towards where we'll meet target rather than where it is.
+func (b *GDBBot) FollowIntercept(target *GDBBot) Command {
+	return b.Move(b.Intercept(target))
+}
@@ -0,0 +1,36 @@
+package client
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ScrappersIO/Player-Samples/geom"
+)
+
+func TestLeadAim(t *testing.T) {
+	tests := []struct {
+		name      string
+		vel       geom.Vec2
+		velKnown  bool
+		updated   time.Duration // Ago
+		shotSpeed float64
+		want      geom.Vec2
+	}{
+		{"unknown velocity", geom.V(0, 80), false, 0, 100, geom.V(60, 0)},
+		{"instant shots", geom.V(0, 80), true, 0, 0, geom.V(60, 0)},
+		{"moving target", geom.V(0, 80), true, 0, 100, geom.V(60, 80)},
+		{"stopped target", geom.V(0, 80), true, 2 * velStale, 100, geom.V(60, 0)},
+		{"too far ahead", geom.V(0, 4), true, 0, 5, geom.V(60, 0)},
+		{"can't catch it", geom.V(200, 0), true, 0, 100, geom.V(60, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bot := &GDBBot{X: 0, Y: 0}
+			target := &GDBBot{X: 60, Y: 0,
+				Vel: tt.vel, VelKnown: tt.velKnown, Updated: time.Now().Add(-tt.updated)}
+			if got := bot.LeadAim(target, tt.shotSpeed); got.Dist(tt.want) > 1e-9 {
+				t.Errorf("LeadAim = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
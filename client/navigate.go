@@ -0,0 +1,60 @@
+package client
+
+import (
+	"sync"
+
+	"github.com/ScrappersIO/Player-Samples/geom"
+	"github.com/ScrappersIO/Player-Samples/nav"
+)
+
+// A waypoint counts as reached within this distance.
+const waypointArrive float64 = BotDiam / 2
+
+// route is one bot's path, and what it was planned on.
+type route struct {
+	follower *nav.Follower
+	m        *nav.Map
+	version  int
+}
+
+var (
+	// Each of our bots' current paths by BID.
+	routes   = make(map[int]*route)
+	routesMu sync.Mutex
+)
+
+// NewNavMap returns a map over the arena with cells a
+// quarter of a bot across, ready for obstacles and danger
+// to be marked on it. ok is false if we don't know where
+// the arena is yet.
+func NewNavMap() (m *nav.Map, ok bool) {
+	walls, ok := Arena()
+	if !ok {
+		return nil, false
+	}
+	return nav.NewMap(walls, BotDiam/4, BotDiam/2), true
+}
+
+// MoveVia returns a command struct for movement towards the
+// next waypoint on a path to dest planned on m. The path is
+// kept between calls and only planned again when dest moves
+// by more than a bot or the map changes.
+func (b *GDBBot) MoveVia(m *nav.Map, dest geom.Vec2) Command {
+	routesMu.Lock()
+	defer routesMu.Unlock()
+
+	r := routes[b.BID]
+	if r == nil || r.m != m || r.version != m.Version() || r.follower.Goal.Dist(dest) > BotDiam {
+		r = &route{nav.NewFollower(m, b.Pos(), dest, waypointArrive), m, m.Version()}
+		routes[b.BID] = r
+		Trace(TraceStrategy, "Planned path", "bid", b.BID, "dest", dest, "waypoints", len(r.follower.Waypoints))
+	}
+	return b.Move(r.follower.Next(b.Pos()))
+}
+
+// forgetRoute drops a bot's path once it's dead.
+func forgetRoute(bid int) {
+	routesMu.Lock()
+	defer routesMu.Unlock()
+	delete(routes, bid)
+}
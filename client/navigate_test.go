@@ -0,0 +1,80 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/ScrappersIO/Player-Samples/geom"
+)
+
+// withArena runs f with the arena set to walls (or unknown,
+// if walls is nil), putting the old one back afterwards.
+func withArena(walls *geom.Rect, f func()) {
+	arenaMu.Lock()
+	oldArena, oldConfigured, oldSeen := arena, arenaConfigured, arenaSeen
+	arenaConfigured, arenaSeen = false, false
+	arenaMu.Unlock()
+	defer func() {
+		arenaMu.Lock()
+		arena, arenaConfigured, arenaSeen = oldArena, oldConfigured, oldSeen
+		arenaMu.Unlock()
+	}()
+	if walls != nil {
+		SetArena(*walls)
+	}
+	f()
+}
+
+func TestNewNavMap(t *testing.T) {
+	withArena(nil, func() {
+		if _, ok := NewNavMap(); ok {
+			t.Error("got a map before knowing the arena")
+		}
+	})
+	walls := geom.R(0, 0, 1000, 800)
+	withArena(&walls, func() {
+		m, ok := NewNavMap()
+		if !ok {
+			t.Fatal("no map for a configured arena")
+		}
+		if m.Bounds != walls || m.Cell != BotDiam/4 {
+			t.Errorf("map over %v with cell %v", m.Bounds, m.Cell)
+		}
+	})
+}
+
+func TestMoveVia(t *testing.T) {
+	walls := geom.R(0, 0, 1000, 1000)
+	withArena(&walls, func() {
+		defer forgetRoute(1)
+		m, _ := NewNavMap()
+		m.BlockRect(geom.R(450, 0, 550, 800))
+		bot := &GDBBot{BID: 1, X: 100, Y: 500}
+		dest := geom.V(900, 500)
+
+		// The first move heads round the wall, not at dest
+		cmd := bot.MoveVia(m, dest)
+		first := geom.FromInt(cmd.X, cmd.Y)
+		if cmd.Cmd != "MOVE" || cmd.BID != 1 || first.Dist(dest) < 1 || m.Blocked(first) {
+			t.Fatalf("first move %+v", cmd)
+		}
+
+		// The same path is followed until dest moves
+		r := routes[1]
+		bot.MoveVia(m, dest.Add(geom.V(0, BotDiam/2)))
+		if routes[1] != r {
+			t.Error("planned again for a small change of dest")
+		}
+		bot.MoveVia(m, dest.Add(geom.V(0, BotDiam*2)))
+		if routes[1] == r {
+			t.Error("kept the old path after dest moved")
+		}
+
+		// Or the map changes
+		r = routes[1]
+		m.BlockCircle(geom.V(200, 200), 10)
+		bot.MoveVia(m, dest.Add(geom.V(0, BotDiam*2)))
+		if routes[1] == r {
+			t.Error("kept the old path after the map changed")
+		}
+	})
+}
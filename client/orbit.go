@@ -0,0 +1,125 @@
+package client
+
+import (
+	"math"
+	"time"
+
+	"github.com/ScrappersIO/Player-Samples/geom"
+	"github.com/ScrappersIO/Player-Samples/nav"
+)
+
+// Direction is which way round a target to orbit.
+type Direction int
+
+const (
+	// CounterClockwise orbits with the angle increasing.
+	CounterClockwise Direction = 1
+	// Clockwise orbits with the angle decreasing.
+	Clockwise Direction = -1
+)
+
+// Orbiter circles one of our bots around a target while
+// keeping it within a range band: close enough to shoot,
+// far enough to back off when the target comes for us.
+// It turns round when the way ahead is blocked, leads into
+// heavier fire, or the bot gets hit.
+type Orbiter struct {
+	MinDist, MaxDist float64 // The range band to keep
+	Step             float64 // How far round to go each move, in radians
+	Dir              Direction
+
+	// Map, if set, has obstacles to turn round at.
+	Map *nav.Map
+
+	// Turn round when the threat ahead is this much worse
+	// than the threat behind. Zero turns this off.
+	FireMargin float64
+
+	// Don't turn round for fire more often than this, so
+	// we don't end up dithering on the spot.
+	ReverseHold time.Duration
+
+	bid         int
+	lastNext    time.Time
+	lastReverse time.Time
+}
+
+// NewOrbiter returns an Orbiter for the given band that
+// steps 10 degrees counter-clockwise at a time.
+func NewOrbiter(minDist, maxDist float64) *Orbiter {
+	return &Orbiter{
+		MinDist:     minDist,
+		MaxDist:     maxDist,
+		Step:        math.Pi / 18,
+		Dir:         CounterClockwise,
+		FireMargin:  0.5,
+		ReverseHold: time.Second,
+	}
+}
+
+// Reverse turns the orbit round.
+func (o *Orbiter) Reverse() {
+	o.Dir = -o.Dir
+	o.lastReverse = time.Now()
+	Trace(TraceStrategy, "Orbit reversed", "bid", o.bid, "dir", o.Dir)
+}
+
+// Next returns where bot should head to carry on orbiting
+// target.
+func (o *Orbiter) Next(bot, target *GDBBot) geom.Vec2 {
+
+	// Hit since last time, unless starting over with a
+	// different bot
+	hit := bot.BID == o.bid && bot.HitAt.After(o.lastNext)
+	o.bid, o.lastNext = bot.BID, time.Now()
+
+	// Keep our distance within the band, and head for the
+	// far edge of it if the target is closing on us.
+	away := bot.Pos().Sub(target.Pos())
+	dist := away.Len()
+	radius := math.Min(math.Max(dist, o.MinDist), o.MaxDist)
+	if vel, ok := target.Velocity(); ok && dist > 0 {
+		closing := vel.Dot(away.Scale(1/dist)) > 0
+		if closing && dist < (o.MinDist+o.MaxDist)/2 {
+			radius = o.MaxDist
+		}
+	}
+
+	angle := target.Pos().AngleTo(bot.Pos())
+	step := func(dir Direction) geom.Vec2 {
+		return target.Pos().Add(geom.Polar(radius, angle+float64(dir)*o.Step))
+	}
+
+	// Turn round for fire, unless we just did
+	if time.Since(o.lastReverse) >= o.ReverseHold {
+		if hit {
+			o.Reverse()
+		} else if o.FireMargin > 0 && Threats.At(step(o.Dir)) > Threats.At(step(-o.Dir))+o.FireMargin {
+			o.Reverse()
+		}
+	}
+
+	// Turn round for walls and obstacles, but only if the
+	// other way is any better.
+	dest := step(o.Dir)
+	if o.blocked(bot.Pos(), dest) && !o.blocked(bot.Pos(), step(-o.Dir)) {
+		o.Reverse()
+		dest = step(o.Dir)
+	}
+	return KeepInside(bot.Pos(), dest, Clamp)
+}
+
+// blocked reports whether dest is inside an obstacle or too
+// close to the walls to get to.
+func (o *Orbiter) blocked(from, dest geom.Vec2) bool {
+	if o.Map != nil && o.Map.Blocked(dest) {
+		return true
+	}
+	return KeepInside(from, dest, Clamp).Dist(dest) > BotDiam/2
+}
+
+// Orbit returns a command struct for movement carrying on
+// around target with o.
+func (b *GDBBot) Orbit(o *Orbiter, target *GDBBot) Command {
+	return b.Move(o.Next(b, target))
+}
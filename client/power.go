@@ -0,0 +1,140 @@
+package client
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/ScrappersIO/Player-Samples/geom"
+)
+
+const (
+	// HurryDist is how far from its destination a bot has to
+	// be before AdaptivePower puts everything into getting there.
+	HurryDist float64 = BotDiam * 3
+	// RecentHit is how long after losing health a bot still
+	// counts as under fire.
+	RecentHit = time.Second
+)
+
+// Split is how a bot's power is shared between fire,
+// movement and shields.
+type Split struct {
+	F, M, S int
+}
+
+// SplitPower shares MaxPow out in proportion to the given
+// weights, so the parts always add up to MaxPow. Negative
+// weights count as zero, and if there's nothing to go on
+// the power is shared evenly.
+func SplitPower(fire, move, shield float64) Split {
+	weights := [3]float64{math.Max(fire, 0), math.Max(move, 0), math.Max(shield, 0)}
+	total := weights[0] + weights[1] + weights[2]
+	if total == 0 {
+		weights, total = [3]float64{1, 1, 1}, 3
+	}
+
+	// Round down, then hand what's left to the parts that
+	// lost the most in rounding.
+	var exact [3]float64
+	var parts [3]int
+	order := []int{0, 1, 2}
+	left := MaxPow
+	for i, w := range weights {
+		exact[i] = w / total * float64(MaxPow)
+		parts[i] = int(exact[i])
+		left -= parts[i]
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		a, b := order[i], order[j]
+		return exact[a]-float64(parts[a]) > exact[b]-float64(parts[b])
+	})
+	for i := 0; i < left; i++ {
+		parts[order[i%3]]++
+	}
+	return Split{parts[0], parts[1], parts[2]}
+}
+
+// Situation is what a power policy has to go on.
+type Situation struct {
+	Bot           *GDBBot
+	ToDest        float64 // How far it is from where it's going
+	TargetInRange bool
+	Hit           bool    // Whether it lost health within RecentHit
+	Threat        float64 // Threats.At where it is
+}
+
+// PowerPolicy decides how a bot should share its power.
+type PowerPolicy func(s Situation) Split
+
+// FixedPower always gives the same split.
+func FixedPower(fire, move, shield int) PowerPolicy {
+	split := SplitPower(float64(fire), float64(move), float64(shield))
+	return func(s Situation) Split {
+		return split
+	}
+}
+
+// AdaptivePower moves hard when far from the destination,
+// fires when there's something in range, and shields when
+// under fire or hurt.
+func AdaptivePower(s Situation) Split {
+
+	// A little of everything all the time: enough movement
+	// to hold position, fire ready for whatever wanders into
+	// range, and a shield just in case.
+	move := math.Max(math.Min(s.ToDest/HurryDist, 1), 0.15)
+	fire := 0.25
+	if s.TargetInRange {
+		fire = 1
+	}
+	shield := 0.5 + math.Min(s.Threat, 1) + 1 - float64(s.Bot.Health)/float64(MaxHealth)
+	if s.Hit {
+		shield += 1
+	}
+
+	// Out of position, getting there matters most
+	if move >= 1 {
+		fire /= 4
+	}
+	return SplitPower(fire, move, shield)
+}
+
+// PowerManager picks each of our bots' power split with a
+// policy.
+type PowerManager struct {
+	Policy PowerPolicy
+}
+
+// NewPowerManager returns a PowerManager using policy.
+func NewPowerManager(policy PowerPolicy) *PowerManager {
+	return &PowerManager{Policy: policy}
+}
+
+// Situation sizes up bot heading for dest and shooting at
+// target (which may be nil).
+func (pm *PowerManager) Situation(bot *GDBBot, dest geom.Vec2, target *GDBBot) Situation {
+	s := Situation{Bot: bot}
+	s.ToDest = bot.Pos().Dist(dest)
+	s.TargetInRange = target != nil && bot.Pos().Dist(target.Pos()) <= Weapons.EffectiveRange()
+	s.Hit = bot.HitWithin(RecentHit)
+	s.Threat = Threats.At(bot.Pos())
+	return s
+}
+
+// Plan returns the power split the policy picks for bot,
+// without sending anything, e.g. to see how much FPow it
+// will have.
+func (pm *PowerManager) Plan(bot *GDBBot, dest geom.Vec2, target *GDBBot) Split {
+	return pm.Policy(pm.Situation(bot, dest, target))
+}
+
+// Power returns a command struct for the power split the
+// policy picks for bot.
+func (pm *PowerManager) Power(bot *GDBBot, dest geom.Vec2, target *GDBBot) Command {
+	s := pm.Situation(bot, dest, target)
+	split := pm.Policy(s)
+	Trace(TraceStrategy, "Power split", "bid", bot.BID, "split", split,
+		"toDest", s.ToDest, "inRange", s.TargetInRange, "hit", s.Hit, "threat", s.Threat)
+	return bot.Power(split.F, split.M, split.S)
+}
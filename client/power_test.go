@@ -0,0 +1,64 @@
+package client
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestSplitPower(t *testing.T) {
+	tests := []struct {
+		name               string
+		fire, move, shield float64
+		want               Split
+	}{
+		{"even", 1, 1, 1, Split{4, 4, 4}},
+		{"all fire", 1, 0, 0, Split{MaxPow, 0, 0}},
+		{"half and half", 0, 2, 2, Split{0, 6, 6}},
+		{"nothing to go on", 0, 0, 0, Split{4, 4, 4}},
+		{"negative counts as zero", -5, 1, 1, Split{0, 6, 6}},
+		{"all negative", -1, -2, -3, Split{4, 4, 4}},
+		{"rounding goes to the biggest remainder", 1, 1, 2, Split{3, 3, 6}},
+		{"rounding", 0.25, 0.15, 1.5, Split{2, 1, 9}},
+		{"tiny weights", 1e-12, 1e-12, 0, Split{6, 6, 0}},
+		{"huge weights", 1e300, 1e300, 1e300, Split{4, 4, 4}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SplitPower(tt.fire, tt.move, tt.shield); got != tt.want {
+				t.Errorf("SplitPower(%v, %v, %v) = %+v, want %+v", tt.fire, tt.move, tt.shield, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestSplitPowerAddsUp checks every split adds up to MaxPow
+// with no negative parts, whatever the weights.
+func TestSplitPowerAddsUp(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	weight := func() float64 {
+		switch rng.Intn(6) {
+		case 0:
+			return 0
+		case 1:
+			return -rng.Float64() * 10
+		case 2:
+			return math.Round(rng.Float64() * 5)
+		}
+		return rng.Float64() * math.Pow(10, float64(rng.Intn(12)-6))
+	}
+	for i := 0; i < 10000; i++ {
+		fire, move, shield := weight(), weight(), weight()
+		split := SplitPower(fire, move, shield)
+		if split.F < 0 || split.M < 0 || split.S < 0 || split.F+split.M+split.S != MaxPow {
+			t.Fatalf("SplitPower(%v, %v, %v) = %+v", fire, move, shield, split)
+		}
+
+		// Nothing goes to a part that didn't ask for any,
+		// unless none of them did
+		if (fire > 0 || move > 0 || shield > 0) &&
+			(fire <= 0 && split.F > 0 || move <= 0 && split.M > 0 || shield <= 0 && split.S > 0) {
+			t.Fatalf("SplitPower(%v, %v, %v) = %+v", fire, move, shield, split)
+		}
+	}
+}
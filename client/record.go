@@ -0,0 +1,135 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// Directions a recorded message can travel.
+const (
+	In  string = "IN"  // From the game to us.
+	Out string = "OUT" // From us to the game.
+)
+
+// RecordEntry is one line of a match recording. Recordings
+// are JSON lines so they can be read back with ReadRecording
+// or picked apart with everyday text tools.
+type RecordEntry struct {
+	T   int64 // Milliseconds since the recording started.
+	Dir string
+	Msg json.RawMessage
+}
+
+// Recorder writes every message to and from the game to a file.
+type Recorder struct {
+	mu    sync.Mutex
+	file  *os.File
+	enc   *json.Encoder
+	start time.Time
+}
+
+// CreateRecorder creates (or truncates) the recording at path.
+func CreateRecorder(path string) (*Recorder, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	r := &Recorder{}
+	r.file = file
+	r.enc = json.NewEncoder(file)
+	r.start = time.Now()
+	return r, nil
+}
+
+// Record appends a message travelling in direction dir. Lines
+// that aren't valid JSON are dropped since the recording
+// couldn't represent them anyway.
+func (r *Recorder) Record(dir string, line []byte) {
+	line = bytes.TrimSpace(line)
+	if !json.Valid(line) {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry := RecordEntry{}
+	entry.T = time.Since(r.start).Milliseconds()
+	entry.Dir = dir
+	entry.Msg = line
+	r.enc.Encode(entry)
+}
+
+// Close closes the recording file.
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.file.Close()
+}
+
+// ReadRecording reads a whole match recording.
+func ReadRecording(r io.Reader) ([]RecordEntry, error) {
+	entries := make([]RecordEntry, 0)
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var entry RecordEntry
+		err := json.Unmarshal(scanner.Bytes(), &entry)
+		if err != nil {
+			return nil, fmt.Errorf("line %v: %v", line, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
+
+// LoadRecording reads the match recording stored at path.
+func LoadRecording(path string) ([]RecordEntry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return ReadRecording(file)
+}
+
+// Decode unmarshals the recorded message into the protocol
+// struct it represents: a *ReadyMsg or *BotMsg for messages
+// from the game and a *Command for messages from us. Game
+// messages of any other type come back as a *Msg.
+func (e RecordEntry) Decode() (interface{}, error) {
+
+	// Our messages are always commands
+	if e.Dir == Out {
+		cmd := &Command{}
+		err := json.Unmarshal(e.Msg, cmd)
+		return cmd, err
+	}
+
+	// Game messages need their type checked first
+	var msg Msg
+	err := json.Unmarshal(e.Msg, &msg)
+	if err != nil {
+		return nil, err
+	}
+	switch msg.Type {
+	case "READY":
+		ready := &ReadyMsg{}
+		err = json.Unmarshal(e.Msg, ready)
+		return ready, err
+	case "BOT":
+		bot := &BotMsg{}
+		err = json.Unmarshal(e.Msg, bot)
+		return bot, err
+	}
+	return &msg, nil
+}
@@ -0,0 +1,126 @@
+package client
+
+import (
+	"math"
+
+	"github.com/ScrappersIO/Player-Samples/geom"
+)
+
+// RallyDepth is how far behind our bots the rally point
+// sits, away from the enemy.
+const RallyDepth float64 = BotDiam * 4
+
+// RallyPoint returns a spot behind our bots, on the far
+// side from the nearest group of enemies, for hurt bots to
+// fall back to. It leaves out bots at or below health
+// below, since they're the ones falling back. ok is false
+// if there's nobody to rally on or nobody to hide from.
+func (gdb *GameDatabase) RallyPoint(below int) (geom.Vec2, bool) {
+	points := make([]geom.Vec2, 0)
+	for _, bot := range gdb.MyBots() {
+		if bot.Health > below {
+			points = append(points, bot.Pos())
+		}
+	}
+	if len(points) == 0 {
+		for _, bot := range gdb.MyBots() {
+			points = append(points, bot.Pos())
+		}
+	}
+	if len(points) == 0 {
+		return geom.Vec2{}, false
+	}
+
+	// Our middle, and which way the enemy is from it
+	var ours geom.Vec2
+	for _, p := range points {
+		ours = ours.Add(p)
+	}
+	ours = ours.Scale(1 / float64(len(points)))
+	enemy, ok := NearestCluster(gdb.EnemyClusters(), ours)
+	if !ok {
+		return geom.Vec2{}, false
+	}
+	toEnemy := enemy.Centroid.Sub(ours).Norm()
+
+	// Our rearmost bot, then a bit further back
+	back := 0.0
+	for _, p := range points {
+		back = math.Min(back, p.Sub(ours).Dot(toEnemy))
+	}
+	rally := ours.Add(toEnemy.Scale(back - RallyDepth))
+	return KeepInside(ours, rally, Clamp), true
+}
+
+// Retreat pulls hurt bots out of the fight to a rally point
+// behind the rest, shielded, and lets them back in if their
+// health recovers. Until then they hang back in a rear role,
+// still shooting at anything in range.
+type Retreat struct {
+	Threshold int // Fall back at or below this health
+	Return    int // Rejoin the fight at or above this health
+
+	falling map[int]bool // By BID, true once at the rally point
+}
+
+// NewRetreat returns a Retreat for bots at or below
+// threshold, which rejoin once they've healed three points
+// above it.
+func NewRetreat(threshold int) *Retreat {
+	return &Retreat{Threshold: threshold, Return: threshold + 3, falling: make(map[int]bool)}
+}
+
+// Override returns commands for bot to fall back or keep
+// to the rear, and false if it's fit to fight and the
+// strategy should carry on with it as usual.
+func (r *Retreat) Override(bot *GDBBot) ([]Command, bool) {
+	arrived, falling := r.falling[bot.BID]
+	switch {
+	case !falling && bot.Health <= r.Threshold:
+		r.falling[bot.BID] = false
+		logEvent("retreat", bot.PID, bot.BID, "")
+		Trace(TraceStrategy, "Falling back", "bid", bot.BID, "health", bot.Health)
+	case falling && bot.Health >= r.Return:
+		delete(r.falling, bot.BID)
+		logEvent("rejoin", bot.PID, bot.BID, "")
+		Trace(TraceStrategy, "Rejoining", "bid", bot.BID, "health", bot.Health)
+		return nil, false
+	case !falling:
+		return nil, false
+	}
+
+	rally, ok := GDB.RallyPoint(r.Threshold)
+	if !ok {
+		return nil, false
+	}
+	cmds := []Command{bot.Move(rally)}
+
+	// On the way back it's all legs and shields, and once
+	// there it covers the others from behind.
+	if !arrived && bot.Pos().Dist(rally) <= BotDiam {
+		r.falling[bot.BID] = true
+		arrived = true
+	}
+	split := SplitPower(0, 1, 1)
+	if arrived {
+		split = SplitPower(1, 0.5, 1.5)
+		if target, ok := r.nearest(bot); ok {
+			cmds = append(cmds, bot.Target(target))
+			SetTarget(bot, target)
+		}
+	}
+	return append(cmds, bot.Power(split.F, split.M, split.S)), true
+}
+
+// nearest returns the closest enemy in effective range of
+// bot.
+func (r *Retreat) nearest(bot *GDBBot) (*GDBBot, bool) {
+	var best *GDBBot
+	bestDist := Weapons.EffectiveRange()
+	for _, enemy := range GDB.TheirBots() {
+		if d := bot.Pos().Dist(enemy.Pos()); d <= bestDist {
+			best, bestDist = enemy, d
+		}
+	}
+	return best, best != nil
+}
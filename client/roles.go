@@ -0,0 +1,153 @@
+package client
+
+import (
+	"sort"
+
+	"github.com/ScrappersIO/Player-Samples/formation"
+	"github.com/ScrappersIO/Player-Samples/geom"
+)
+
+// Role is a job one of our bots is doing.
+type Role string
+
+const (
+	// Striker does the shooting.
+	Striker Role = "striker"
+	// Tank soaks up fire.
+	Tank Role = "tank"
+	// Screen stands between the enemy and someone worth
+	// protecting.
+	Screen Role = "screen"
+	// Scout goes round the side to find and harry the enemy.
+	Scout Role = "scout"
+	// Reserve hangs back until it's needed.
+	Reserve Role = "reserve"
+)
+
+// RolePower is how each role shares its power by default.
+var RolePower = map[Role]Split{
+	Striker: {8, 4, 0},
+	Tank:    {0, 4, 8},
+	Screen:  {3, 3, 6},
+	Scout:   {2, 8, 2},
+	Reserve: {2, 2, 8},
+}
+
+// RoleNeed asks for Count bots in Role, preferring the ones
+// Score rates highest.
+type RoleNeed struct {
+	Role  Role
+	Count int
+	Score func(bot *GDBBot) float64
+}
+
+// Healthiest scores bots by how much health they have.
+func Healthiest(bot *GDBBot) float64 {
+	return float64(bot.Health) / float64(MaxHealth)
+}
+
+// Weakest scores bots by how much health they've lost.
+func Weakest(bot *GDBBot) float64 {
+	return 1 - Healthiest(bot)
+}
+
+// Roles hands out roles to our bots by score, filling the
+// needs as well as it can overall. It runs again every time
+// it's asked, so when a bot dies or gets hurt someone else
+// takes over its job; Stickiness keeps bots in their current
+// roles unless a change is clearly better. Bots left over
+// are the Reserve.
+type Roles struct {
+	Needs      []RoleNeed
+	Stickiness float64
+
+	current map[int]Role // By BID
+}
+
+// NewRoles returns a Roles filling needs.
+func NewRoles(needs ...RoleNeed) *Roles {
+	return &Roles{Needs: needs, Stickiness: 0.2, current: make(map[int]Role)}
+}
+
+// Assign gives each of bots a role, returning them by BID.
+func (r *Roles) Assign(bots []*GDBBot) map[int]Role {
+
+	// Sort so equal scores always break the same way
+	bots = append([]*GDBBot{}, bots...)
+	sort.Slice(bots, func(i, j int) bool {
+		return bots[i].BID < bots[j].BID
+	})
+
+	// One column per place to fill, padded out with reserve
+	// places so everyone can have one.
+	places := make([]RoleNeed, 0)
+	for _, need := range r.Needs {
+		for i := 0; i < need.Count; i++ {
+			places = append(places, need)
+		}
+	}
+	for len(places) < len(bots) {
+		places = append(places, RoleNeed{Role: Reserve})
+	}
+
+	cost := make([][]float64, len(bots))
+	for i, bot := range bots {
+		cost[i] = make([]float64, len(places))
+		for j, place := range places {
+			score := 0.0
+			if place.Score != nil {
+				score = place.Score(bot)
+			}
+			if r.current[bot.BID] == place.Role {
+				score += r.Stickiness
+			}
+			cost[i][j] = -score
+		}
+	}
+
+	roles := make(map[int]Role)
+	for i, j := range formation.Hungarian(cost) {
+		bot, role := bots[i], places[j].Role
+		roles[bot.BID] = role
+		if old, ok := r.current[bot.BID]; !ok || old != role {
+			logEvent("role", bot.PID, bot.BID, string(role))
+			Trace(TraceStrategy, "Role changed", "bid", bot.BID, "from", old, "to", role)
+		}
+	}
+	r.current = roles
+	return roles
+}
+
+// Of returns the role last given to the bot with bid.
+func (r *Roles) Of(bid int) Role {
+	role, ok := r.current[bid]
+	if !ok {
+		return Reserve
+	}
+	return role
+}
+
+// PowerFor returns a command struct for the power split
+// that goes with role.
+func (b *GDBBot) PowerFor(role Role) Command {
+	split := RolePower[role]
+	return b.Power(split.F, split.M, split.S)
+}
+
+// Screen returns a command struct for movement to stand
+// between protect and threat, a bot and a half in front of
+// protect (center to center), so there's half a bot's gap
+// between them.
+func (b *GDBBot) Screen(protect, threat *GDBBot) Command {
+	toward := threat.Pos().Sub(protect.Pos()).Norm()
+	return b.MoveWithin(protect.Pos().Add(toward.Scale(BotDiam*1.5)), Clamp)
+}
+
+// Scout returns a command struct for movement round the
+// side of target, at the edge of its reach, on whichever
+// side the bot is already.
+func (b *GDBBot) Scout(target *GDBBot) Command {
+	side := target.Pos().AngleTo(b.Pos())
+	dest := target.Pos().Add(geom.Polar(Weapons.MaxRange(), side+0.5))
+	return b.MoveWithin(dest, Clamp)
+}
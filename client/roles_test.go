@@ -0,0 +1,71 @@
+package client
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ScrappersIO/Player-Samples/geom"
+)
+
+func TestScreen(t *testing.T) {
+	tests := []struct {
+		name            string
+		protect, threat geom.Vec2
+		want            geom.Vec2
+	}{
+		{"threat to the right", geom.V(100, 100), geom.V(900, 100), geom.V(190, 100)},
+		{"threat below", geom.V(100, 100), geom.V(100, 900), geom.V(100, 190)},
+		{"threat at an angle", geom.V(100, 100), geom.V(400, 500), geom.V(154, 172)},
+	}
+	withArena(nil, func() {
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				bot := &GDBBot{BID: 1, X: 500, Y: 500}
+				protect := &GDBBot{X: int(tt.protect.X), Y: int(tt.protect.Y)}
+				threat := &GDBBot{X: int(tt.threat.X), Y: int(tt.threat.Y)}
+				cmd := bot.Screen(protect, threat)
+				got := geom.FromInt(cmd.X, cmd.Y)
+				if cmd.Cmd != "MOVE" || cmd.BID != 1 || got.Dist(tt.want) > 1 {
+					t.Errorf("Screen = %+v, want a move to %v", cmd, tt.want)
+				}
+
+				// Half a bot's gap between them
+				if gap := got.Dist(tt.protect) - BotDiam; math.Abs(gap-BotDiam/2) > 1 {
+					t.Errorf("gap of %v, want %v", gap, BotDiam/2)
+				}
+			})
+		}
+	})
+}
+
+func TestScout(t *testing.T) {
+	tests := []struct {
+		name string
+		bot  geom.Vec2
+	}{
+		{"from the left", geom.V(0, 500)},
+		{"from above", geom.V(500, 0)},
+		{"from close by", geom.V(520, 530)},
+	}
+	target := &GDBBot{X: 500, Y: 500}
+	withArena(nil, func() {
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				bot := &GDBBot{BID: 1, X: int(tt.bot.X), Y: int(tt.bot.Y)}
+				cmd := bot.Scout(target)
+				got := geom.FromInt(cmd.X, cmd.Y)
+
+				// At the edge of its range, a little round
+				// from where the bot is
+				if d := got.Dist(target.Pos()); math.Abs(d-Weapons.MaxRange()) > 1 {
+					t.Errorf("Scout goes %v from the target, want %v", d, Weapons.MaxRange())
+				}
+				from := target.Pos().AngleTo(tt.bot)
+				to := target.Pos().AngleTo(got)
+				if turn := math.Remainder(to-from, 2*math.Pi); math.Abs(turn-0.5) > 0.01 {
+					t.Errorf("Scout turns %v round the target, want 0.5", turn)
+				}
+			})
+		}
+	})
+}
@@ -0,0 +1,108 @@
+package client
+
+import (
+	"math"
+	"sync"
+
+	"github.com/ScrappersIO/Player-Samples/geom"
+)
+
+// The golden angle spreads bots that want the exact same
+// spot evenly around it.
+var goldenAngle = math.Pi * (3 - math.Sqrt(5))
+
+var (
+	// How far apart our bots' destinations are kept.
+	spacing   = BotDiam
+	spacingMu sync.Mutex
+)
+
+// SetSpacing sets how far apart Send keeps the destinations
+// of our bots. Anything below BotDiam is raised to BotDiam,
+// except zero which turns separation off.
+func SetSpacing(d float64) {
+	spacingMu.Lock()
+	defer spacingMu.Unlock()
+	if d > 0 && d < BotDiam {
+		d = BotDiam
+	}
+	spacing = d
+}
+
+// Spacing returns how far apart our bots' destinations are
+// kept, or zero if separation is off.
+func Spacing() float64 {
+	spacingMu.Lock()
+	defer spacingMu.Unlock()
+	return spacing
+}
+
+// separate moves the destination of a MOVE command away from
+// where our other bots are headed (or are, if they aren't
+// headed anywhere) so the swarm doesn't pile up on one spot.
+// Bots still end up as close to the goal as they can.
+func separate(cmd Command) Command {
+	space := Spacing()
+	if cmd.Cmd != "MOVE" || space <= 0 {
+		return cmd
+	}
+
+	// Where is everybody else going?
+	var me *GDBBot
+	others := make([]geom.Vec2, 0)
+	lastSentMu.Lock()
+	for _, bot := range GDB.MyBots() {
+		if bot.BID == cmd.BID {
+			me = bot
+			continue
+		}
+		dest := bot.Pos()
+		if move, ok := lastSent[sentKey{"MOVE", bot.BID}]; ok {
+			dest = geom.FromInt(move.X, move.Y)
+		}
+		others = append(others, dest)
+	}
+	lastSentMu.Unlock()
+	if me == nil {
+		return cmd
+	}
+
+	// Push the destination out of everybody's way. Moving
+	// away from one bot can crowd another, so go round a
+	// few times.
+	goal := geom.FromInt(cmd.X, cmd.Y)
+	dest := goal
+	for pass := 0; pass < 4; pass++ {
+		moved := false
+		for _, other := range others {
+			away := dest.Sub(other)
+			if away.Len() >= space {
+				continue
+			}
+
+			// Stay on our side of the spot if we can
+			if away.Len() == 0 {
+				away = me.Pos().Sub(other)
+			}
+			if away.Len() == 0 {
+				away = geom.Polar(1, float64(me.BID)*goldenAngle)
+			}
+			dest = other.Add(away.Norm().Scale(space))
+			moved = true
+		}
+		if !moved {
+			break
+		}
+	}
+
+	// Don't let spreading out push anybody into a wall
+	if walls, ok := Arena(); ok && walls.Inset(BotDiam/2).Contains(goal) {
+		dest = KeepInside(me.Pos(), dest, Clamp)
+	}
+
+	if dest != goal {
+		Trace(TraceCmd, "Separated move", "bid", cmd.BID, "goal", goal, "dest", dest)
+		cmd.X, cmd.Y = dest.Int()
+	}
+	return cmd
+}
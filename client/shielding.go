@@ -0,0 +1,135 @@
+package client
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultShieldBoost is how much power reactive shielding
+	// moves into SPow when a bot is hit.
+	DefaultShieldBoost = 6
+	// DefaultShieldQuiet is how long a bot has to go without
+	// being hit before its shield starts to come back down.
+	DefaultShieldQuiet = 2 * time.Second
+	// ShieldEase is how long each point of boost takes to
+	// come off once the bot has gone quiet.
+	ShieldEase = 250 * time.Millisecond
+)
+
+// shieldState is reactive shielding's view of one bot.
+type shieldState struct {
+	requested Command // The last POWER the strategy asked for
+	asked     bool
+	boost     int // The boost last applied
+}
+
+var (
+	// Reactive shielding settings, off unless the strategy
+	// turns it on.
+	shieldOn    bool
+	shieldBoost = DefaultShieldBoost
+	shieldQuiet = DefaultShieldQuiet
+	shields     = make(map[int]*shieldState) // By BID
+	shieldMu    sync.Mutex
+)
+
+// SetReactiveShield turns reactive shielding on or off. When
+// it's on, a bot that loses health has boost points of power
+// moved into SPow straight away, taken from FPow first and
+// then MPow. Once it has gone quiet for quiet, the boost
+// comes off a point every ShieldEase until the bot is back
+// to the split the strategy asked for.
+func SetReactiveShield(on bool, boost int, quiet time.Duration) {
+	shieldMu.Lock()
+	defer shieldMu.Unlock()
+	shieldOn, shieldBoost, shieldQuiet = on, boost, quiet
+	if !on {
+		shields = make(map[int]*shieldState)
+	}
+}
+
+// shieldPower notes the split the strategy asked for and
+// returns what to send instead, with any boost applied.
+func shieldPower(cmd Command) Command {
+	if cmd.Cmd != "POWER" {
+		return cmd
+	}
+	shieldMu.Lock()
+	defer shieldMu.Unlock()
+	if !shieldOn {
+		return cmd
+	}
+	s := shieldFor(cmd.BID)
+	s.requested, s.asked = cmd, true
+	if bot := GDB.find(BotRef{GDB.PID, cmd.BID}); bot != nil {
+		s.ease(bot)
+	}
+	return boosted(cmd, s.boost)
+}
+
+// shieldObserve reacts to a BOT message about one of our
+// bots. It returns a POWER to send and true if the bot's
+// shield should go up or come down.
+func shieldObserve(bot *GDBBot) (Command, bool) {
+	shieldMu.Lock()
+	defer shieldMu.Unlock()
+	if !shieldOn {
+		return Command{}, false
+	}
+	s := shieldFor(bot.BID)
+	if !s.asked || !s.ease(bot) {
+		return Command{}, false
+	}
+	return boosted(s.requested, s.boost), true
+}
+
+// ease sets the boost for how long it's been since the bot
+// was last hit: all of it until it has been quiet for
+// shieldQuiet, then a point less every ShieldEase. It goes
+// by the time rather than counting messages, as a bot
+// that's keeping still and out of the fight hears little.
+// It reports whether the boost changed. The caller holds
+// shieldMu.
+func (s *shieldState) ease(bot *GDBBot) bool {
+	boost := 0
+	if !bot.HitAt.IsZero() {
+		quiet := time.Since(bot.HitAt) - shieldQuiet
+		boost = shieldBoost
+		if quiet >= 0 {
+			boost = max(shieldBoost-int(quiet/ShieldEase), 0)
+		}
+	}
+	if boost == s.boost {
+		return false
+	}
+	if boost > s.boost {
+		Trace(TraceStrategy, "Shield up", "bid", bot.BID, "health", bot.Health)
+	} else {
+		Trace(TraceStrategy, "Shield easing off", "bid", bot.BID, "boost", boost)
+	}
+	s.boost = boost
+	return true
+}
+
+// shieldFor returns a bot's shield state, creating it if
+// need be. The caller holds shieldMu.
+func shieldFor(bid int) *shieldState {
+	s, ok := shields[bid]
+	if !ok {
+		s = &shieldState{}
+		shields[bid] = s
+	}
+	return s
+}
+
+// boosted moves up to boost points of power into SPow,
+// from FPow first and then MPow.
+func boosted(cmd Command, boost int) Command {
+	fromFire := min(boost, cmd.FPow)
+	fromMove := min(boost-fromFire, cmd.MPow)
+	cmd.FPow -= fromFire
+	cmd.MPow -= fromMove
+	cmd.SPow += fromFire + fromMove
+	return cmd
+}
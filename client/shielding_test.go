@@ -0,0 +1,58 @@
+package client
+
+import (
+	"testing"
+	"time"
+)
+
+func TestShieldEase(t *testing.T) {
+	tests := []struct {
+		name  string
+		hit   bool
+		ago   time.Duration
+		boost int
+	}{
+		{"never hit", false, 0, 0},
+		{"just hit", true, 0, DefaultShieldBoost},
+		{"not quiet yet", true, DefaultShieldQuiet - ShieldEase, DefaultShieldBoost},
+		{"just gone quiet", true, DefaultShieldQuiet + ShieldEase/2, DefaultShieldBoost},
+		{"one step down", true, DefaultShieldQuiet + ShieldEase*3/2, DefaultShieldBoost - 1},
+		{"three steps down", true, DefaultShieldQuiet + ShieldEase*7/2, DefaultShieldBoost - 3},
+		{"all the way down", true, DefaultShieldQuiet + ShieldEase*DefaultShieldBoost, 0},
+		{"long ago", true, time.Hour, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bot := &GDBBot{BID: 1}
+			if tt.hit {
+				bot.HitAt = time.Now().Add(-tt.ago)
+			}
+			s := &shieldState{}
+			changed := s.ease(bot)
+			if s.boost != tt.boost || changed != (tt.boost != 0) {
+				t.Errorf("boost %v (changed %v), want %v", s.boost, changed, tt.boost)
+			}
+		})
+	}
+}
+
+func TestBoosted(t *testing.T) {
+	tests := []struct {
+		name  string
+		power Command
+		boost int
+		want  Command
+	}{
+		{"no boost", Command{FPow: 6, MPow: 6}, 0, Command{FPow: 6, MPow: 6}},
+		{"from fire", Command{FPow: 6, MPow: 6}, 4, Command{FPow: 2, MPow: 6, SPow: 4}},
+		{"fire then move", Command{FPow: 2, MPow: 10}, 6, Command{MPow: 6, SPow: 6}},
+		{"not enough", Command{FPow: 1, MPow: 1, SPow: 10}, 6, Command{SPow: 12}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := boosted(tt.power, tt.boost); got != tt.want {
+				t.Errorf("boosted = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
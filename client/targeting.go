@@ -0,0 +1,182 @@
+package client
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ScrappersIO/Player-Samples/geom"
+)
+
+// How much longer a shielded bot is assumed to take to
+// kill, until we know better.
+const shieldedToughness = 2
+
+// Scorer rates one thing about a target for a shooter at
+// from. Scores run from 0 (worst) to 1 (best) so that the
+// weights decide what matters most.
+type Scorer struct {
+	Name   string
+	Weight float64
+	Score  func(from geom.Vec2, target *GDBBot) float64
+}
+
+// LowHealth prefers targets with less health left.
+func LowHealth(weight float64) Scorer {
+	return Scorer{"health", weight, func(from geom.Vec2, target *GDBBot) float64 {
+		return 1 - float64(target.Health)/float64(MaxHealth)
+	}}
+}
+
+// Nearby prefers closer targets, scoring nothing for
+// targets reach away or further.
+func Nearby(weight, reach float64) Scorer {
+	return Scorer{"distance", weight, func(from geom.Vec2, target *GDBBot) float64 {
+		return math.Max(0, 1-from.Dist(target.Pos())/reach)
+	}}
+}
+
+// Threatening prefers targets that have been firing the
+// most, going by the threat map.
+func Threatening(weight float64) Scorer {
+	return Scorer{"threat", weight, func(from geom.Vec2, target *GDBBot) float64 {
+		return Threats.FireRate(target.PID, target.BID)
+	}}
+}
+
+// Hurting prefers targets that have done us the most
+// damage in the last HurtWindow, going by matched hits.
+func Hurting(weight float64) Scorer {
+	return Scorer{"hurting", weight, func(from geom.Vec2, target *GDBBot) float64 {
+		damage := GDB.DamageBy(BotRef{target.PID, target.BID}, time.Now().Add(-HurtWindow))
+		return math.Min(float64(damage)/(float64(MaxHealth)/4), 1)
+	}}
+}
+
+// QuickKill prefers targets we can finish soonest, given
+// how much damage per second we can put into them.
+func QuickKill(weight, damagePerSec float64) Scorer {
+	return Scorer{"time-to-kill", weight, func(from geom.Vec2, target *GDBBot) float64 {
+		toughness := float64(target.Health)
+		if target.Shield {
+			toughness *= shieldedToughness
+		}
+		return 1 / (1 + toughness/damagePerSec)
+	}}
+}
+
+// Focused prefers targets more of our bots are already
+// shooting, so fire concentrates instead of spreading.
+func Focused(weight float64) Scorer {
+	return Scorer{"focus", weight, func(from geom.Vec2, target *GDBBot) float64 {
+		mine := len(GDB.MyBots())
+		if mine == 0 {
+			return 0
+		}
+		return float64(targetedBy(BotRef{target.PID, target.BID})) / float64(mine)
+	}}
+}
+
+// Unshielded prefers targets without their shield up.
+func Unshielded(weight float64) Scorer {
+	return Scorer{"shield", weight, func(from geom.Vec2, target *GDBBot) float64 {
+		if target.Shield {
+			return 0
+		}
+		return 1
+	}}
+}
+
+// Choice is a target a Selector picked and why.
+type Choice struct {
+	Target *GDBBot
+	Total  float64
+	Parts  map[string]float64 // Weighted score by scorer name
+	Kept   bool               // Whether hysteresis kept the old target
+}
+
+// Why describes the scores behind a choice, biggest first.
+func (c Choice) Why() string {
+	names := make([]string, 0, len(c.Parts))
+	for name := range c.Parts {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return c.Parts[names[i]] > c.Parts[names[j]]
+	})
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = fmt.Sprintf("%s=%.2f", name, c.Parts[name])
+	}
+	why := fmt.Sprintf("total=%.2f %s", c.Total, strings.Join(parts, " "))
+	if c.Kept {
+		why += " (kept)"
+	}
+	return why
+}
+
+// Selector picks targets by adding up weighted scores. It
+// sticks with a shooter's previous target unless another
+// beats it by Hysteresis, so bots don't flip between two
+// targets that score about the same.
+type Selector struct {
+	Scorers    []Scorer
+	Hysteresis float64
+
+	current map[int]BotRef // By shooter
+}
+
+// NewSelector returns a Selector using scorers.
+func NewSelector(scorers ...Scorer) *Selector {
+	return &Selector{Scorers: scorers, Hysteresis: 0.1, current: make(map[int]BotRef)}
+}
+
+// Choose picks a target from candidates for the shooter
+// with the given ID (a BID, or anything else the strategy
+// likes) at from. It returns false if there are none.
+func (s *Selector) Choose(shooter int, from geom.Vec2, candidates []*GDBBot) (Choice, bool) {
+	var best, kept Choice
+	found, hadCurrent := false, false
+	current, ok := s.current[shooter]
+
+	for _, target := range candidates {
+		choice := s.score(from, target)
+		if !found || choice.Total > best.Total {
+			best, found = choice, true
+		}
+		if ok && current == (BotRef{target.PID, target.BID}) {
+			kept, hadCurrent = choice, true
+		}
+	}
+	if !found {
+		return Choice{}, false
+	}
+
+	// Only switch if it's clearly worth it
+	if hadCurrent && best.Target != kept.Target && best.Total < kept.Total+s.Hysteresis {
+		best = kept
+		best.Kept = true
+	}
+
+	ref := BotRef{best.Target.PID, best.Target.BID}
+	if !ok || ref != current {
+		logEvent("target", best.Target.PID, best.Target.BID, fmt.Sprintf("shooter %v: %s", shooter, best.Why()))
+	}
+	s.current[shooter] = ref
+	Trace(TraceStrategy, "Chose target", "shooter", shooter,
+		"tpid", best.Target.PID, "tbid", best.Target.BID, "why", best.Why())
+	return best, true
+}
+
+// score adds up every scorer's opinion of target.
+func (s *Selector) score(from geom.Vec2, target *GDBBot) Choice {
+	choice := Choice{Target: target, Parts: make(map[string]float64)}
+	for _, scorer := range s.Scorers {
+		part := scorer.Weight * scorer.Score(from, target)
+		choice.Parts[scorer.Name] += part
+		choice.Total += part
+	}
+	return choice
+}
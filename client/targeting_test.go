@@ -0,0 +1,83 @@
+package client
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ScrappersIO/Player-Samples/geom"
+)
+
+func TestScorers(t *testing.T) {
+
+	// Two of our bots, one of them already shooting enemy 2/1
+	oldGDB := GDB
+	defer func() { GDB = oldGDB }()
+	GDB = GameDatabase{PID: 1, Bots: []GDBBot{
+		{PID: 1, BID: 1, Health: MaxHealth},
+		{PID: 1, BID: 2, Health: MaxHealth},
+	}}
+	statusMu.Lock()
+	oldTargets := targets
+	targets = map[int]BotRef{1: {2, 1}}
+	statusMu.Unlock()
+	defer func() {
+		statusMu.Lock()
+		targets = oldTargets
+		statusMu.Unlock()
+	}()
+
+	from := geom.V(0, 0)
+	tests := []struct {
+		name   string
+		scorer Scorer
+		target GDBBot
+		want   float64
+	}{
+		{"low health, full", LowHealth(1), GDBBot{Health: MaxHealth}, 0},
+		{"low health, half", LowHealth(1), GDBBot{Health: MaxHealth / 2}, 0.5},
+		{"nearby, on top", Nearby(1, 100), GDBBot{}, 1},
+		{"nearby, half way", Nearby(1, 100), GDBBot{X: 50}, 0.5},
+		{"nearby, out of reach", Nearby(1, 100), GDBBot{X: 300}, 0},
+		{"quick kill, unshielded", QuickKill(1, 4), GDBBot{Health: 4}, 0.5},
+		{"quick kill, shielded", QuickKill(1, 4), GDBBot{Health: 4, Shield: true}, 1.0 / 3},
+		{"quick kill, dead already", QuickKill(1, 4), GDBBot{Health: 0}, 1},
+		{"focused, one of two on it", Focused(1), GDBBot{PID: 2, BID: 1}, 0.5},
+		{"focused, nobody on it", Focused(1), GDBBot{PID: 2, BID: 2}, 0},
+		{"unshielded", Unshielded(1), GDBBot{}, 1},
+		{"shielded", Unshielded(1), GDBBot{Shield: true}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.scorer.Score(from, &tt.target); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("%v scored %v, want %v", tt.scorer.Name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSelector(t *testing.T) {
+	near := &GDBBot{PID: 2, BID: 1, X: 10, Health: MaxHealth}
+	far := &GDBBot{PID: 2, BID: 2, X: 50, Health: MaxHealth}
+	hurt := &GDBBot{PID: 2, BID: 3, X: 60, Health: 1}
+	s := NewSelector(Nearby(1, 100), LowHealth(1))
+
+	if _, ok := s.Choose(1, geom.V(0, 0), nil); ok {
+		t.Error("chose a target out of none")
+	}
+
+	// The nearest first, kept when another is about as good
+	choice, _ := s.Choose(1, geom.V(0, 0), []*GDBBot{far, near})
+	if choice.Target != near || choice.Kept {
+		t.Fatalf("chose %+v, want the nearest", choice)
+	}
+	choice, _ = s.Choose(1, geom.V(32, 0), []*GDBBot{far, near})
+	if choice.Target != near || !choice.Kept {
+		t.Fatalf("chose %+v, want to keep the nearest", choice)
+	}
+
+	// But not when another is clearly better
+	choice, _ = s.Choose(1, geom.V(32, 0), []*GDBBot{far, near, hurt})
+	if choice.Target != hurt || choice.Kept {
+		t.Fatalf("chose %+v, want the hurt one", choice)
+	}
+}
@@ -0,0 +1,217 @@
+package client
+
+import (
+	"math"
+	"sync"
+
+	"github.com/ScrappersIO/Player-Samples/geom"
+)
+
+// ThreatCell is the size of a cell in the threat map.
+const ThreatCell float64 = BotDiam / 2
+
+const (
+	// How much each BOT message counts towards an enemy's
+	// fire rate.
+	fireSmoothing = 0.2
+	// Fire rate assumed for an enemy we've only just met.
+	initialFireRate = 0.5
+	// Beyond range, threat fades out over this distance to
+	// allow for enemies stepping closer before they fire.
+	threatFade = BotDiam * 2
+)
+
+// ThreatMap estimates how much enemy fire each part of the
+// arena is likely to take, from where enemies are, how often
+// they've been firing and how far shots go (Weapons.MaxRange).
+type ThreatMap struct {
+	mu sync.Mutex
+
+	sources map[BotRef]*threatSource
+
+	// The threat at the center of each cell over the arena,
+	// kept up to date as enemies move, and the range it was
+	// worked out with.
+	bounds     geom.Rect
+	reach      float64
+	cols, rows int
+	cells      []float64
+}
+
+// threatSource is one enemy as far as the threat map cares.
+type threatSource struct {
+	pos  geom.Vec2
+	rate float64 // Fraction of messages it fired in, smoothed
+}
+
+// Threats is the threat map for the current game, kept up
+// to date as BOT messages arrive.
+var Threats = &ThreatMap{sources: make(map[BotRef]*threatSource)}
+
+// observe updates the map from an enemy's BOT message.
+func (t *ThreatMap) observe(msg BotMsg) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	// Take the enemy off the grid while it changes
+	ref := BotRef{msg.PID, msg.BID}
+	src, ok := t.sources[ref]
+	if ok {
+		t.spread(src, -1)
+	}
+	if msg.Health <= 0 {
+		delete(t.sources, ref)
+		return
+	}
+
+	if !ok {
+		src = &threatSource{rate: initialFireRate}
+		t.sources[ref] = src
+	}
+	src.pos = msg.Pos()
+	fired := 0.0
+	if msg.Fired {
+		fired = 1
+	}
+	src.rate += (fired - src.rate) * fireSmoothing
+
+	// Put it back, unless the whole grid needs filling again
+	if !t.refill() {
+		t.spread(src, 1)
+	}
+}
+
+// FireRate returns the fraction of BOT messages an enemy
+// has fired in lately.
+func (t *ThreatMap) FireRate(pid, bid int) float64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	src, ok := t.sources[BotRef{pid, bid}]
+	if !ok {
+		return initialFireRate
+	}
+	return src.rate
+}
+
+// At returns the threat at p: roughly how many enemy shots
+// per BOT message could land there.
+func (t *ThreatMap) At(p geom.Vec2) float64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.at(p)
+}
+
+// SafestNear returns the point within radius of goal that
+// is least threatened, preferring points closer to goal
+// when the threat is about the same.
+func (t *ThreatMap) SafestNear(goal geom.Vec2, radius float64) geom.Vec2 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	best, bestScore := goal, math.Inf(1)
+	score := func(p geom.Vec2) {
+		if p.Dist(goal) > radius {
+			return
+		}
+		// Distance only breaks ties, so it's worth a tenth
+		// of a shot at most.
+		s := t.at(p) + 0.1*p.Dist(goal)/math.Max(radius, 1)
+		if s < bestScore {
+			best, bestScore = p, s
+		}
+	}
+
+	score(goal)
+	for y := -radius; y <= radius; y += ThreatCell {
+		for x := -radius; x <= radius; x += ThreatCell {
+			score(goal.Add(geom.V(x, y)))
+		}
+	}
+	return KeepInside(goal, best, Clamp)
+}
+
+// at reads the threat at p off the grid, or works it out if
+// p is off the grid or there isn't one yet.
+func (t *ThreatMap) at(p geom.Vec2) float64 {
+	col, row := t.cellOf(p)
+	if t.cells == nil || col < 0 || row < 0 || col >= t.cols || row >= t.rows {
+		return t.threat(p, Weapons.MaxRange())
+	}
+
+	// Taking enemies off and putting them back can leave a
+	// rounding error where there's no threat at all
+	return math.Max(t.cells[row*t.cols+col], 0)
+}
+
+// threat adds up every enemy's contribution at p, for
+// enemies that can shoot reach.
+func (t *ThreatMap) threat(p geom.Vec2, reach float64) float64 {
+	total := 0.0
+	for _, src := range t.sources {
+		total += src.threat(p, reach)
+	}
+	return total
+}
+
+// threat returns one enemy's contribution at p.
+func (src *threatSource) threat(p geom.Vec2, reach float64) float64 {
+	d := src.pos.Dist(p)
+	switch {
+	case d <= reach:
+		return src.rate
+	case d < reach+threatFade:
+		return src.rate * (1 - (d-reach)/threatFade)
+	}
+	return 0
+}
+
+// spread adds one enemy's threat to (sign 1) or takes it
+// off (sign -1) the grid cells within its reach.
+func (t *ThreatMap) spread(src *threatSource, sign float64) {
+	if t.cells == nil {
+		return
+	}
+	far := geom.V(t.reach+threatFade, t.reach+threatFade)
+	col0, row0 := t.cellOf(src.pos.Sub(far))
+	col1, row1 := t.cellOf(src.pos.Add(far))
+	for row := max(row0, 0); row <= min(row1, t.rows-1); row++ {
+		for col := max(col0, 0); col <= min(col1, t.cols-1); col++ {
+			t.cells[row*t.cols+col] += sign * src.threat(t.center(col, row), t.reach)
+		}
+	}
+}
+
+// refill fills the grid from scratch if the arena has
+// changed since it was last filled, or the range has moved
+// by more than a cell, and reports whether it did. There's
+// no grid until we know where the arena is.
+func (t *ThreatMap) refill() bool {
+	walls, ok := Arena()
+	reach := Weapons.MaxRange()
+	if !ok || (t.cells != nil && walls == t.bounds && math.Abs(reach-t.reach) <= ThreatCell) {
+		return false
+	}
+
+	size := walls.Size()
+	t.bounds, t.reach = walls, reach
+	t.cols = int(math.Ceil(size.X/ThreatCell)) + 1
+	t.rows = int(math.Ceil(size.Y/ThreatCell)) + 1
+	t.cells = make([]float64, t.cols*t.rows)
+	for row := 0; row < t.rows; row++ {
+		for col := 0; col < t.cols; col++ {
+			t.cells[row*t.cols+col] = t.threat(t.center(col, row), reach)
+		}
+	}
+	return true
+}
+
+// center returns the middle of a grid cell.
+func (t *ThreatMap) center(col, row int) geom.Vec2 {
+	return t.bounds.Min.Add(geom.V(float64(col)*ThreatCell, float64(row)*ThreatCell))
+}
+
+// cellOf returns the grid cell containing p.
+func (t *ThreatMap) cellOf(p geom.Vec2) (col, row int) {
+	rel := p.Sub(t.bounds.Min)
+	return int(math.Round(rel.X / ThreatCell)), int(math.Round(rel.Y / ThreatCell))
+}
@@ -0,0 +1,123 @@
+package client
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ScrappersIO/Player-Samples/geom"
+)
+
+// withWeapons runs f with a weapon model that hasn't seen
+// anything yet.
+func withWeapons(f func()) {
+	old := Weapons
+	defer func() { Weapons = old }()
+	Weapons = &WeaponModel{}
+	f()
+}
+
+func TestThreatGrid(t *testing.T) {
+	walls := geom.R(0, 0, 1200, 900)
+	withArena(&walls, func() {
+		withWeapons(func() {
+			threats := &ThreatMap{sources: make(map[BotRef]*threatSource)}
+			msgs := []BotMsg{
+				{PID: 2, BID: 1, X: 100, Y: 100, Health: 12},
+				{PID: 2, BID: 2, X: 600, Y: 450, Health: 12, Fired: true, HitX: 700, HitY: 450},
+				{PID: 2, BID: 1, X: 160, Y: 130, Health: 12},
+				{PID: 3, BID: 1, X: 1100, Y: 800, Health: 12, Fired: true, HitX: 1100, HitY: 100},
+				{PID: 2, BID: 2, X: 640, Y: 400, Health: 10},
+				{PID: 2, BID: 1, X: 160, Y: 130, Health: 0},
+				{PID: 3, BID: 1, X: 1050, Y: 820, Health: 12, Fired: true, HitX: 1000, HitY: 800},
+				{PID: 2, BID: 2, X: 640, Y: 400, Health: 10, Fired: true, HitX: 640, HitY: 1105},
+			}
+			for i, msg := range msgs {
+				if msg.Fired {
+					Weapons.observeShot(msg.Pos().Dist(msg.HitPos()))
+				}
+				threats.observe(msg)
+
+				// The grid keeps its range until the weapon model's
+				// moves by more than a cell
+				if d := math.Abs(threats.reach - Weapons.MaxRange()); d > ThreatCell {
+					t.Fatalf("after message %v: grid range %v, weapons say %v", i, threats.reach, Weapons.MaxRange())
+				}
+
+				// Every cell should match working it out from scratch
+				for row := 0; row < threats.rows; row++ {
+					for col := 0; col < threats.cols; col++ {
+						p := threats.center(col, row)
+						if got, want := threats.At(p), threats.threat(p, threats.reach); math.Abs(got-want) > 1e-9 {
+							t.Fatalf("after message %v: At(%v) = %v, want %v", i, p, got, want)
+						}
+					}
+				}
+			}
+			if threats.reach != 700 {
+				t.Errorf("grid range %v, want 700: 705 is within a cell of it", threats.reach)
+			}
+		})
+	})
+}
+
+func TestThreatAt(t *testing.T) {
+	withArena(nil, func() {
+		withWeapons(func() {
+			threats := &ThreatMap{sources: make(map[BotRef]*threatSource)}
+			threats.observe(BotMsg{PID: 2, BID: 1, X: 0, Y: 0, Health: 12})
+			rate := initialFireRate * (1 - fireSmoothing)
+
+			tests := []struct {
+				name string
+				p    geom.Vec2
+				want float64
+			}{
+				{"on top of it", geom.V(0, 0), rate},
+				{"at the edge of its range", geom.V(DefaultFireRange, 0), rate},
+				{"fading out", geom.V(DefaultFireRange+threatFade/2, 0), rate / 2},
+				{"out of reach", geom.V(DefaultFireRange+threatFade, 0), 0},
+			}
+			for _, tt := range tests {
+				t.Run(tt.name, func(t *testing.T) {
+					if got := threats.At(tt.p); math.Abs(got-tt.want) > 1e-9 {
+						t.Errorf("At(%v) = %v, want %v", tt.p, got, tt.want)
+					}
+				})
+			}
+		})
+	})
+}
+
+func TestSafestNear(t *testing.T) {
+	walls := geom.R(0, 0, 2000, 1000)
+	withArena(&walls, func() {
+		withWeapons(func() {
+			threats := &ThreatMap{sources: make(map[BotRef]*threatSource)}
+			threats.observe(BotMsg{PID: 2, BID: 1, X: 500, Y: 500, Health: 12})
+
+			tests := []struct {
+				name      string
+				goal      geom.Vec2
+				radius    float64
+				want      geom.Vec2
+				maxDist   float64 // From want
+				maxThreat float64
+			}{
+				{"already safe", geom.V(1800, 500), 300, geom.V(1800, 500), 0, 0},
+				{"step out of range", geom.V(1000, 500), 600, geom.V(1100, 500), ThreatCell, 0},
+				{"nowhere safe in reach", geom.V(500, 500), 100, geom.V(500, 500), 0, 1},
+			}
+			for _, tt := range tests {
+				t.Run(tt.name, func(t *testing.T) {
+					got := threats.SafestNear(tt.goal, tt.radius)
+					if got.Dist(tt.want) > tt.maxDist {
+						t.Errorf("SafestNear = %v, want within %v of %v", got, tt.maxDist, tt.want)
+					}
+					if threat := threats.At(got); threat > tt.maxThreat {
+						t.Errorf("threat at %v is %v", got, threat)
+					}
+				})
+			}
+		})
+	})
+}
@@ -0,0 +1,191 @@
+package client
+
+import (
+	"math"
+	"sort"
+	"sync"
+)
+
+const (
+	// DefaultFireRange is our guess at how far a bot can
+	// shoot, until we've seen one do it.
+	DefaultFireRange float64 = BotDiam * 8
+	// RangeShare is the share of recent shots that go no
+	// further than the maximum range. Leaving out the longest
+	// few keeps one odd shot from stretching it for good.
+	RangeShare = 0.95
+	// EffectiveShare is the share of hits that land within
+	// the effective range.
+	EffectiveShare = 0.9
+	// How many shots or hits we need before trusting what
+	// they say about range, or about damage at one FPow level.
+	minWeaponSamples = 5
+	// How many of the latest shots the maximum range goes by.
+	rangeWindow = 100
+)
+
+// DamageLevel is what we've seen shots at one FPow do.
+type DamageLevel struct {
+	FPow    int
+	Damage  float64 // Average damage to an unshielded bot
+	Samples int
+}
+
+// WeaponModel learns how far shots reach and how much
+// damage they do at each FPow, from shots and hits seen
+// during the match.
+type WeaponModel struct {
+	mu sync.Mutex
+
+	shotDists []float64 // How far recent shots travelled, hit or miss
+	nextShot  int       // Where in shotDists the next one goes once it's full
+	longest   float64   // Longest shot seen
+	hitDists  []float64 // How far each hit travelled
+
+	damage [MaxPow + 1]DamageLevel
+}
+
+// Weapons is the weapon model for the current game.
+var Weapons = &WeaponModel{}
+
+// observeShot notes a shot travelling dist, replacing the
+// oldest once we have rangeWindow of them.
+func (w *WeaponModel) observeShot(dist float64) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.shotDists) < rangeWindow {
+		w.shotDists = append(w.shotDists, dist)
+	} else {
+		w.shotDists[w.nextShot] = dist
+		w.nextShot = (w.nextShot + 1) % rangeWindow
+	}
+	if dist > w.longest {
+		w.longest = dist
+		Trace(TraceDB, "Longest shot", "range", dist)
+	}
+}
+
+// observeHit notes a hit. If we know the FPow behind it and
+// the victim had no shield (fpow is -1 otherwise), it also
+// tells us what that FPow does.
+func (w *WeaponModel) observeHit(h Hit, fpow int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.hitDists = append(w.hitDists, h.Dist)
+	if fpow < 0 || fpow > MaxPow || h.Fatal {
+		return
+	}
+	level := &w.damage[fpow]
+	level.FPow = fpow
+	level.Damage += (float64(h.Damage) - level.Damage) / float64(level.Samples+1)
+	level.Samples++
+	Trace(TraceDB, "Weapon damage", "fpow", fpow, "damage", level.Damage, "samples", level.Samples)
+}
+
+// MaxRange returns how far shots go: the distance that
+// RangeShare of the last rangeWindow shots stayed within.
+// Until there have been enough shots to tell it's the
+// longest one seen or DefaultFireRange, whichever is
+// further.
+func (w *WeaponModel) MaxRange() float64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.maxRange()
+}
+
+func (w *WeaponModel) maxRange() float64 {
+	if len(w.shotDists) < minWeaponSamples {
+		return max(w.longest, DefaultFireRange)
+	}
+	return quantile(w.shotDists, RangeShare)
+}
+
+// EffectiveRange returns the distance within which most
+// hits (EffectiveShare of them) have landed, which is where
+// shooting is worth it. Until there have been enough hits
+// to tell it's MaxRange.
+func (w *WeaponModel) EffectiveRange() float64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.hitDists) < minWeaponSamples {
+		return w.maxRange()
+	}
+	return quantile(w.hitDists, EffectiveShare)
+}
+
+// quantile returns the smallest of dists that share of
+// them are no greater than. It doesn't change dists.
+func quantile(dists []float64, share float64) float64 {
+	sorted := append([]float64{}, dists...)
+	sort.Float64s(sorted)
+	i := int(math.Ceil(share*float64(len(sorted)))) - 1
+	return sorted[max(i, 0)]
+}
+
+// PerFPow returns the damage each point of FPow does to an
+// unshielded bot, fitted through every level we've seen, or
+// DefaultDamagePerFPow if we haven't seen any.
+func (w *WeaponModel) PerFPow() float64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.perFPow()
+}
+
+func (w *WeaponModel) perFPow() float64 {
+
+	// Least squares through the origin, weighted by samples
+	var num, den float64
+	for _, level := range w.damage {
+		n, f := float64(level.Samples), float64(level.FPow)
+		num += n * f * level.Damage
+		den += n * f * f
+	}
+	if den == 0 {
+		return DefaultDamagePerFPow
+	}
+	return num / den
+}
+
+// Damage returns how much a shot at fpow takes off an
+// unshielded bot: what we've seen at that level if we've
+// seen enough of it, or else the fitted rate.
+func (w *WeaponModel) Damage(fpow int) float64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if fpow >= 0 && fpow <= MaxPow && w.damage[fpow].Samples >= minWeaponSamples {
+		return w.damage[fpow].Damage
+	}
+	return w.perFPow() * float64(fpow)
+}
+
+// WeaponStats is everything the weapon model has learned,
+// for the debug endpoint.
+type WeaponStats struct {
+	MaxRange       float64
+	EffectiveRange float64
+	PerFPow        float64
+	Levels         []DamageLevel
+}
+
+// Stats returns what the model has learned so far.
+func (w *WeaponModel) Stats() WeaponStats {
+	return WeaponStats{
+		MaxRange:       w.MaxRange(),
+		EffectiveRange: w.EffectiveRange(),
+		PerFPow:        w.PerFPow(),
+		Levels:         w.Levels(),
+	}
+}
+
+// Levels returns every FPow level we've seen hits at.
+func (w *WeaponModel) Levels() []DamageLevel {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	levels := make([]DamageLevel, 0)
+	for _, level := range w.damage {
+		if level.Samples > 0 {
+			levels = append(levels, level)
+		}
+	}
+	return levels
+}
@@ -0,0 +1,89 @@
+package client
+
+import (
+	"testing"
+)
+
+func TestMaxRange(t *testing.T) {
+
+	// shots returns n shots of dist
+	shots := func(n int, dist float64) []float64 {
+		dists := make([]float64, n)
+		for i := range dists {
+			dists[i] = dist
+		}
+		return dists
+	}
+
+	tests := []struct {
+		name  string
+		shots []float64
+		want  float64
+	}{
+		{"nothing seen", nil, DefaultFireRange},
+		{"a few short shots", shots(3, 50), DefaultFireRange},
+		{"a few long shots", []float64{50, 600, 300}, 600},
+		{"enough short shots", shots(minWeaponSamples, 50), 50},
+		{"one stray long shot", append(shots(99, 300), 900), 300},
+		{"a few long ones", append(shots(90, 300), shots(10, 400)...), 400},
+		{"old shots forgotten", append(shots(50, 900), shots(rangeWindow, 300)...), 300},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &WeaponModel{}
+			for _, d := range tt.shots {
+				w.observeShot(d)
+			}
+			if got := w.MaxRange(); got != tt.want {
+				t.Errorf("MaxRange = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEffectiveRange(t *testing.T) {
+	tests := []struct {
+		name string
+		hits []float64
+		want float64
+	}{
+		{"too few hits", []float64{10, 20}, DefaultFireRange},
+		{"nine in ten close", []float64{100, 10, 20, 30, 40, 50, 60, 70, 80, 90}, 90},
+		{"all the same", []float64{60, 60, 60, 60, 60}, 60},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &WeaponModel{}
+			for _, d := range tt.hits {
+				w.observeHit(Hit{Dist: d}, -1)
+			}
+			if got := w.EffectiveRange(); got != tt.want {
+				t.Errorf("EffectiveRange = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWeaponDamage(t *testing.T) {
+	w := &WeaponModel{}
+	for i := 0; i < minWeaponSamples; i++ {
+		w.observeHit(Hit{Damage: 4}, 2)
+	}
+	w.observeHit(Hit{Damage: 9}, 3)
+	w.observeHit(Hit{Damage: 12}, -1) // Ignored, as we don't know the FPow
+	w.observeHit(Hit{Damage: 1, Fatal: true}, 4)
+
+	tests := []struct {
+		fpow int
+		want float64
+	}{
+		{2, 4},                                   // Seen enough
+		{3, 3.0 * (5*2*4 + 3*9) / (5*2*2 + 3*3)}, // Fitted
+		{0, 0},
+	}
+	for _, tt := range tests {
+		if got := w.Damage(tt.fpow); got != tt.want {
+			t.Errorf("Damage(%v) = %v, want %v", tt.fpow, got, tt.want)
+		}
+	}
+}
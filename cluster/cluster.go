@@ -0,0 +1,93 @@
+// Package cluster finds groups of points that sit close
+// together, such as an enemy swarm that has split up.
+package cluster
+
+import (
+	"math"
+
+	"github.com/ScrappersIO/Player-Samples/geom"
+)
+
+// Noise labels a point that isn't in any group.
+const Noise = -1
+
+// DBSCAN groups points that are within eps of each other,
+// following chains of neighbours, so groups can be any
+// shape and there's no need to know how many there are. A
+// point needs minPts points (itself included) within eps to
+// start or extend a group; points that never make it into
+// one are labelled Noise. It returns each point's group and
+// how many groups there are.
+func DBSCAN(points []geom.Vec2, eps float64, minPts int) (labels []int, n int) {
+	const unseen = -2
+	labels = make([]int, len(points))
+	for i := range labels {
+		labels[i] = unseen
+	}
+
+	neighbours := func(i int) []int {
+		near := make([]int, 0)
+		for j, p := range points {
+			if points[i].Dist(p) <= eps {
+				near = append(near, j)
+			}
+		}
+		return near
+	}
+
+	for i := range points {
+		if labels[i] != unseen {
+			continue
+		}
+		near := neighbours(i)
+		if len(near) < minPts {
+			labels[i] = Noise
+			continue
+		}
+
+		// Start a new group and grow it as far as it goes
+		labels[i] = n
+		for k := 0; k < len(near); k++ {
+			j := near[k]
+			if labels[j] == Noise {
+				labels[j] = n // A border point, reached but not a core
+			}
+			if labels[j] != unseen {
+				continue
+			}
+			labels[j] = n
+			if more := neighbours(j); len(more) >= minPts {
+				near = append(near, more...)
+			}
+		}
+		n++
+	}
+	return labels, n
+}
+
+// Centroid returns the average of points.
+func Centroid(points []geom.Vec2) geom.Vec2 {
+	var sum geom.Vec2
+	for _, p := range points {
+		sum = sum.Add(p)
+	}
+	if len(points) == 0 {
+		return sum
+	}
+	return sum.Scale(1 / float64(len(points)))
+}
+
+// Spread returns the root mean square distance of points
+// from their centroid.
+func Spread(points []geom.Vec2) float64 {
+	if len(points) == 0 {
+		return 0
+	}
+	c := Centroid(points)
+	sum := 0.0
+	for _, p := range points {
+		d := p.Dist(c)
+		sum += d * d
+	}
+	return math.Sqrt(sum / float64(len(points)))
+}
@@ -0,0 +1,92 @@
+package cluster
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ScrappersIO/Player-Samples/geom"
+)
+
+func TestDBSCAN(t *testing.T) {
+	tests := []struct {
+		name   string
+		points []geom.Vec2
+		eps    float64
+		minPts int
+		labels []int
+		n      int
+	}{
+		{"no points", nil, 10, 1, []int{}, 0},
+		{"one point", []geom.Vec2{geom.V(5, 5)}, 10, 1, []int{0}, 1},
+		{"one point, not enough", []geom.Vec2{geom.V(5, 5)}, 10, 2, []int{Noise}, 0},
+		{
+			"two apart",
+			[]geom.Vec2{geom.V(0, 0), geom.V(100, 0)},
+			10, 1, []int{0, 1}, 2,
+		},
+		{
+			"chain",
+			[]geom.Vec2{geom.V(0, 0), geom.V(20, 0), geom.V(10, 0), geom.V(30, 0)},
+			10, 1, []int{0, 0, 0, 0}, 1,
+		},
+		{
+			"noise",
+			[]geom.Vec2{geom.V(0, 0), geom.V(5, 0), geom.V(10, 0), geom.V(100, 0)},
+			10, 3, []int{0, 0, 0, Noise}, 1,
+		},
+		{
+			"border point seen first",
+			[]geom.Vec2{geom.V(11, 0), geom.V(0, 0), geom.V(1, 0), geom.V(2, 0)},
+			10, 3, []int{0, 0, 0, 0}, 1,
+		},
+		{
+			"border point doesn't extend the group",
+			[]geom.Vec2{geom.V(0, 0), geom.V(1, 0), geom.V(2, 0), geom.V(3, 0), geom.V(12.5, 0), geom.V(21, 0)},
+			10, 4, []int{0, 0, 0, 0, 0, Noise}, 1,
+		},
+		{
+			"two groups",
+			[]geom.Vec2{
+				geom.V(0, 0), geom.V(500, 500), geom.V(5, 5),
+				geom.V(505, 495), geom.V(250, 250), geom.V(10, 0),
+			},
+			20, 2, []int{0, 1, 0, 1, Noise, 0}, 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			labels, n := DBSCAN(tt.points, tt.eps, tt.minPts)
+			if n != tt.n || len(labels) != len(tt.labels) {
+				t.Fatalf("DBSCAN = %v, %v; want %v, %v", labels, n, tt.labels, tt.n)
+			}
+			for i := range labels {
+				if labels[i] != tt.labels[i] {
+					t.Fatalf("DBSCAN = %v, %v; want %v, %v", labels, n, tt.labels, tt.n)
+				}
+			}
+		})
+	}
+}
+
+func TestCentroidSpread(t *testing.T) {
+	tests := []struct {
+		name     string
+		points   []geom.Vec2
+		centroid geom.Vec2
+		spread   float64
+	}{
+		{"none", nil, geom.V(0, 0), 0},
+		{"one", []geom.Vec2{geom.V(3, 4)}, geom.V(3, 4), 0},
+		{"square", []geom.Vec2{geom.V(0, 0), geom.V(2, 0), geom.V(0, 2), geom.V(2, 2)}, geom.V(1, 1), math.Sqrt2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if c := Centroid(tt.points); c.Dist(tt.centroid) > 1e-9 {
+				t.Errorf("Centroid = %v, want %v", c, tt.centroid)
+			}
+			if s := Spread(tt.points); math.Abs(s-tt.spread) > 1e-9 {
+				t.Errorf("Spread = %v, want %v", s, tt.spread)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package formation
+
+import (
+	"math"
+	"sort"
+
+	"github.com/ScrappersIO/Player-Samples/geom"
+)
+
+// Member is a bot taking part in a formation.
+type Member struct {
+	ID  int
+	Pos geom.Vec2
+}
+
+// Assign returns which slot each member should take so the
+// total distance travelled is as small as possible. The
+// result is indexed like members. There must be at least as
+// many slots as members.
+func Assign(members []Member, slots []geom.Vec2) []int {
+	cost := make([][]float64, len(members))
+	for i, m := range members {
+		cost[i] = make([]float64, len(slots))
+		for j, slot := range slots {
+			cost[i][j] = m.Pos.Dist(slot)
+		}
+	}
+	return Hungarian(cost)
+}
+
+// Assigner hands out slots like Assign, but remembers where
+// it sent each member last time. Members favour slots near
+// their old ones, so when a bot dies and the slots shift the
+// rest close ranks instead of reshuffling.
+type Assigner struct {
+	// How much a member's old slot counts against its
+	// current position when picking a new one.
+	Stickiness float64
+
+	prev map[int]geom.Vec2
+}
+
+// NewAssigner returns an Assigner with a sensible stickiness.
+func NewAssigner() *Assigner {
+	return &Assigner{Stickiness: 0.5, prev: make(map[int]geom.Vec2)}
+}
+
+// Assign returns the slot for each member, by member ID.
+func (a *Assigner) Assign(members []Member, slots []geom.Vec2) map[int]geom.Vec2 {
+
+	// Sort so equal costs always break the same way
+	members = append([]Member{}, members...)
+	sort.Slice(members, func(i, j int) bool {
+		return members[i].ID < members[j].ID
+	})
+
+	cost := make([][]float64, len(members))
+	for i, m := range members {
+		cost[i] = make([]float64, len(slots))
+		prev, ok := a.prev[m.ID]
+		for j, slot := range slots {
+			cost[i][j] = m.Pos.Dist(slot)
+			if ok {
+				cost[i][j] += a.Stickiness * prev.Dist(slot)
+			}
+		}
+	}
+
+	result := make(map[int]geom.Vec2)
+	next := make(map[int]geom.Vec2)
+	for i, j := range Hungarian(cost) {
+		result[members[i].ID] = slots[j]
+		next[members[i].ID] = slots[j]
+	}
+	a.prev = next
+	return result
+}
+
+// Hungarian solves the assignment problem: given cost[i][j]
+// of giving row i column j, it returns the column for each
+// row with the smallest total cost. There must be at least
+// as many columns as rows.
+func Hungarian(cost [][]float64) []int {
+	n := len(cost)
+	if n == 0 {
+		return nil
+	}
+	m := len(cost[0])
+
+	// Potentials for rows and columns, which row each column
+	// is matched to (1-based, 0 is none), and the path back
+	// through the augmenting tree.
+	u := make([]float64, n+1)
+	v := make([]float64, m+1)
+	match := make([]int, m+1)
+	way := make([]int, m+1)
+
+	for i := 1; i <= n; i++ {
+		match[0] = i
+		col := 0
+		minv := make([]float64, m+1)
+		used := make([]bool, m+1)
+		for j := range minv {
+			minv[j] = math.Inf(1)
+		}
+
+		// Grow the tree until it reaches a free column
+		for match[col] != 0 {
+			used[col] = true
+			row := match[col]
+			delta := math.Inf(1)
+			next := 0
+			for j := 1; j <= m; j++ {
+				if used[j] {
+					continue
+				}
+				cur := cost[row-1][j-1] - u[row] - v[j]
+				if cur < minv[j] {
+					minv[j] = cur
+					way[j] = col
+				}
+				if minv[j] < delta {
+					delta = minv[j]
+					next = j
+				}
+			}
+			for j := 0; j <= m; j++ {
+				if used[j] {
+					u[match[j]] += delta
+					v[j] -= delta
+				} else {
+					minv[j] -= delta
+				}
+			}
+			col = next
+		}
+
+		// Flip the matches along the path
+		for col != 0 {
+			prev := way[col]
+			match[col] = match[prev]
+			col = prev
+		}
+	}
+
+	assign := make([]int, n)
+	for j := 1; j <= m; j++ {
+		if match[j] != 0 {
+			assign[match[j]-1] = j - 1
+		}
+	}
+	return assign
+}
@@ -0,0 +1,89 @@
+package formation
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestHungarian(t *testing.T) {
+	tests := []struct {
+		name string
+		cost [][]float64
+		want []int
+	}{
+		{"empty", nil, nil},
+		{"one", [][]float64{{3}}, []int{0}},
+		{"diagonal", [][]float64{{1, 9}, {9, 1}}, []int{0, 1}},
+		{"crossed", [][]float64{{9, 1}, {1, 9}}, []int{1, 0}},
+		{"greedy is wrong", [][]float64{{1, 2}, {1, 9}}, []int{1, 0}},
+		{"more columns", [][]float64{{5, 1, 9}, {5, 2, 9}}, []int{1, 0}},
+		{"three", [][]float64{{4, 1, 3}, {2, 0, 5}, {3, 2, 2}}, []int{1, 0, 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Hungarian(tt.cost)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Hungarian = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("Hungarian = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+// TestHungarianBruteForce checks Hungarian finds the same
+// total as trying every assignment, on small square and
+// rectangular problems.
+func TestHungarianBruteForce(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for n := 1; n <= 5; n++ {
+		for m := n; m <= 6; m++ {
+			for trial := 0; trial < 20; trial++ {
+				cost := make([][]float64, n)
+				for i := range cost {
+					cost[i] = make([]float64, m)
+					for j := range cost[i] {
+						// Small integers so there are ties
+						cost[i][j] = float64(rng.Intn(10))
+					}
+				}
+
+				got := Hungarian(cost)
+				used := make(map[int]bool)
+				total := 0.0
+				for i, j := range got {
+					if j < 0 || j >= m || used[j] {
+						t.Fatalf("%vx%v %v: bad assignment %v", n, m, cost, got)
+					}
+					used[j] = true
+					total += cost[i][j]
+				}
+				if best := bruteForce(cost, 0, make([]bool, m)); math.Abs(total-best) > 1e-9 {
+					t.Fatalf("%vx%v %v: total %v, best is %v", n, m, cost, total, best)
+				}
+			}
+		}
+	}
+}
+
+// bruteForce returns the smallest total cost of assigning
+// rows from row on to columns not yet used.
+func bruteForce(cost [][]float64, row int, used []bool) float64 {
+	if row == len(cost) {
+		return 0
+	}
+	best := math.Inf(1)
+	for j := range used {
+		if used[j] {
+			continue
+		}
+		used[j] = true
+		best = math.Min(best, cost[row][j]+bruteForce(cost, row+1, used))
+		used[j] = false
+	}
+	return best
+}
@@ -0,0 +1,123 @@
+// Package formation arranges a group of bots into shapes
+// (lines, wedges, dishes...) and decides which bot goes to
+// which spot so nobody has to cross the whole group to
+// get there.
+package formation
+
+import (
+	"math"
+
+	"github.com/ScrappersIO/Player-Samples/geom"
+)
+
+// Shape returns n slot offsets at least spacing apart,
+// relative to an anchor at the origin facing along +X.
+type Shape func(n int, spacing float64) []geom.Vec2
+
+// Formation is a shape placed on the arena.
+type Formation struct {
+	Shape   Shape
+	Anchor  geom.Vec2 // Where the shape is centered (its front, for a wedge)
+	Facing  float64   // Direction in radians the formation faces
+	Spacing float64   // Distance between neighbouring slots
+}
+
+// Slots returns the arena positions of n slots.
+func (f Formation) Slots(n int) []geom.Vec2 {
+	slots := f.Shape(n, f.Spacing)
+	for i, slot := range slots {
+		slots[i] = f.Anchor.Add(slot.Rotate(f.Facing))
+	}
+	return slots
+}
+
+// Line puts bots shoulder to shoulder, facing forward.
+func Line(n int, spacing float64) []geom.Vec2 {
+	slots := make([]geom.Vec2, n)
+	for i := range slots {
+		slots[i] = geom.V(0, (float64(i)-float64(n-1)/2)*spacing)
+	}
+	return slots
+}
+
+// Column puts bots one behind the other.
+func Column(n int, spacing float64) []geom.Vec2 {
+	slots := make([]geom.Vec2, n)
+	for i := range slots {
+		slots[i] = geom.V(-float64(i)*spacing, 0)
+	}
+	return slots
+}
+
+// Wedge puts one bot at the point and the rest in two
+// arms trailing back from it, alternating sides.
+func Wedge(n int, spacing float64) []geom.Vec2 {
+	// Arms sweep back 45 degrees either side of the line
+	// of travel, with spacing between bots along each arm.
+	const sweep = math.Pi / 4
+	slots := make([]geom.Vec2, n)
+	for i := 1; i < n; i++ {
+		row := float64((i + 1) / 2)
+		side := 1.0
+		if i%2 == 0 {
+			side = -1
+		}
+		slots[i] = geom.Polar(row*spacing, math.Pi-side*sweep)
+	}
+	return slots
+}
+
+// Circle surrounds the anchor, starting at the front.
+func Circle(n int, spacing float64) []geom.Vec2 {
+	slots := make([]geom.Vec2, n)
+	if n == 1 {
+		return slots
+	}
+	radius := math.Max(spacing*float64(n)/(2*math.Pi), spacing/2/math.Sin(math.Pi/float64(n)))
+	for i := range slots {
+		slots[i] = geom.Polar(radius, 2*math.Pi*float64(i)/float64(n))
+	}
+	return slots
+}
+
+// Grid packs bots into rows as close to square as it can,
+// centered on the anchor.
+func Grid(n int, spacing float64) []geom.Vec2 {
+	cols := int(math.Ceil(math.Sqrt(float64(n))))
+	if cols == 0 {
+		return nil
+	}
+	rows := (n + cols - 1) / cols
+	slots := make([]geom.Vec2, n)
+	for i := range slots {
+		row, col := i/cols, i%cols
+		across := float64(cols)
+		if row == rows-1 && n%cols != 0 {
+			across = float64(n % cols) // Center a short last row
+		}
+		x := (float64(rows-1)/2 - float64(row)) * spacing
+		y := (float64(col) - (across-1)/2) * spacing
+		slots[i] = geom.V(x, y)
+	}
+	return slots
+}
+
+// Arc returns a dish shape: bots along a circle of the given
+// radius whose center lies radius ahead of the anchor, so the
+// dish curves around whatever it faces. The middle slot sits
+// on the anchor.
+func Arc(radius float64) Shape {
+	return func(n int, spacing float64) []geom.Vec2 {
+		if radius <= 0 {
+			return Line(n, spacing)
+		}
+		center := geom.V(radius, 0)
+		step := spacing / radius // Radians between neighbours
+		slots := make([]geom.Vec2, n)
+		for i := range slots {
+			offset := (float64(i) - float64(n-1)/2) * step
+			slots[i] = center.Add(geom.Polar(radius, math.Pi+offset))
+		}
+		return slots
+	}
+}
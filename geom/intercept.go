@@ -0,0 +1,48 @@
+package geom
+
+import "math"
+
+// Intercept returns where something leaving from with the
+// given speed can meet a target at pos moving with velocity
+// vel, and how long that takes. ok is false if it can never
+// catch the target.
+func Intercept(from Vec2, speed float64, pos, vel Vec2) (meet Vec2, t float64, ok bool) {
+
+	// Solve |pos + vel*t - from| = speed*t for the
+	// smallest t that isn't in the past.
+	d := pos.Sub(from)
+	a := vel.Dot(vel) - speed*speed
+	b := 2 * d.Dot(vel)
+	c := d.Dot(d)
+
+	switch {
+	case c == 0:
+		return pos, 0, true
+	case math.Abs(a) < 1e-9:
+		// Same speed as the target: only one answer
+		if b >= 0 {
+			return Vec2{}, 0, false
+		}
+		t = -c / b
+	default:
+		disc := b*b - 4*a*c
+		if disc < 0 {
+			return Vec2{}, 0, false
+		}
+		root := math.Sqrt(disc)
+		t1 := (-b - root) / (2 * a)
+		t2 := (-b + root) / (2 * a)
+		t = math.Inf(1)
+		if t1 > 0 {
+			t = t1
+		}
+		if t2 > 0 && t2 < t {
+			t = t2
+		}
+		if math.IsInf(t, 1) {
+			return Vec2{}, 0, false
+		}
+	}
+
+	return pos.Add(vel.Scale(t)), t, true
+}
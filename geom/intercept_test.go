@@ -0,0 +1,46 @@
+package geom
+
+import (
+	"math"
+	"testing"
+)
+
+func TestIntercept(t *testing.T) {
+	tests := []struct {
+		name     string
+		from     Vec2
+		speed    float64
+		pos, vel Vec2
+		meet     Vec2
+		t        float64
+		ok       bool
+	}{
+		{"still target", V(0, 0), 10, V(100, 0), V(0, 0), V(100, 0), 10, true},
+		{"already there", V(5, 5), 10, V(5, 5), V(3, 4), V(5, 5), 0, true},
+		{"crossing", V(0, 0), 5, V(30, 0), V(0, 4), V(30, 40), 10, true},
+		{"faster target coming at us", V(0, 0), 10, V(100, 0), V(-30, 0), V(25, 0), 2.5, true},
+		{"same speed coming at us", V(0, 0), 10, V(100, 0), V(-10, 0), V(50, 0), 5, true},
+		{"same speed running away", V(0, 0), 10, V(100, 0), V(10, 0), Vec2{}, 0, false},
+		{"faster target running away", V(0, 0), 10, V(10, 0), V(20, 0), Vec2{}, 0, false},
+		{"faster target passing by", V(0, 0), 1, V(100, 50), V(-20, 0), Vec2{}, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			meet, tm, ok := Intercept(tt.from, tt.speed, tt.pos, tt.vel)
+			if ok != tt.ok {
+				t.Fatalf("ok = %v, want %v", ok, tt.ok)
+			}
+			if !ok {
+				return
+			}
+			if meet.Dist(tt.meet) > 1e-9 || math.Abs(tm-tt.t) > 1e-9 {
+				t.Errorf("got %v at %v, want %v at %v", meet, tm, tt.meet, tt.t)
+			}
+
+			// Both should be at the meeting point at that time
+			if d := tt.from.Dist(meet); math.Abs(d-tt.speed*tm) > 1e-9 {
+				t.Errorf("we travel %v in %v, not %v", d, tm, tt.speed*tm)
+			}
+		})
+	}
+}
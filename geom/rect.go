@@ -0,0 +1,114 @@
+package geom
+
+import "math"
+
+// Rect is an axis aligned rectangle. Min is always the
+// corner with the smaller coordinates.
+type Rect struct {
+	Min, Max Vec2
+}
+
+// R returns the rectangle with corners x0,y0 and x1,y1.
+func R(x0, y0, x1, y1 float64) Rect {
+	return Rect{
+		Vec2{math.Min(x0, x1), math.Min(y0, y1)},
+		Vec2{math.Max(x0, x1), math.Max(y0, y1)},
+	}
+}
+
+// Size returns the width and height of r as a vector.
+func (r Rect) Size() Vec2 {
+	return r.Max.Sub(r.Min)
+}
+
+// Center returns the middle of r.
+func (r Rect) Center() Vec2 {
+	return r.Min.Lerp(r.Max, 0.5)
+}
+
+// Contains reports whether p is inside r or on its edge.
+func (r Rect) Contains(p Vec2) bool {
+	return p.X >= r.Min.X && p.X <= r.Max.X &&
+		p.Y >= r.Min.Y && p.Y <= r.Max.Y
+}
+
+// Inset returns r shrunk by d on every side. A negative d
+// grows it. If r is too small to shrink by d it collapses
+// to its center along that axis.
+func (r Rect) Inset(d float64) Rect {
+	out := Rect{r.Min.Add(V(d, d)), r.Max.Sub(V(d, d))}
+	c := r.Center()
+	if out.Min.X > out.Max.X {
+		out.Min.X, out.Max.X = c.X, c.X
+	}
+	if out.Min.Y > out.Max.Y {
+		out.Min.Y, out.Max.Y = c.Y, c.Y
+	}
+	return out
+}
+
+// Union returns the smallest rectangle containing r and p.
+func (r Rect) Union(p Vec2) Rect {
+	return Rect{
+		Vec2{math.Min(r.Min.X, p.X), math.Min(r.Min.Y, p.Y)},
+		Vec2{math.Max(r.Max.X, p.X), math.Max(r.Max.Y, p.Y)},
+	}
+}
+
+// Clamp returns the point in r closest to p.
+func (r Rect) Clamp(p Vec2) Vec2 {
+	return Vec2{
+		math.Max(r.Min.X, math.Min(r.Max.X, p.X)),
+		math.Max(r.Min.Y, math.Min(r.Max.Y, p.Y)),
+	}
+}
+
+// Reflect bounces p off the edges of r until it lands
+// inside, as if it had travelled there in a straight line.
+func (r Rect) Reflect(p Vec2) Vec2 {
+	return Vec2{fold(p.X, r.Min.X, r.Max.X), fold(p.Y, r.Min.Y, r.Max.Y)}
+}
+
+// fold reflects v back and forth between lo and hi.
+func fold(v, lo, hi float64) float64 {
+	span := hi - lo
+	if span <= 0 {
+		return lo
+	}
+	t := math.Mod(v-lo, 2*span)
+	if t < 0 {
+		t += 2 * span
+	}
+	if t > span {
+		t = 2*span - t
+	}
+	return lo + t
+}
+
+// Cut returns how far along the line from a to b you can go
+// before leaving r: b itself if it's inside, otherwise the
+// point where the line crosses the edge. If a is already
+// outside, the closest point in r to b is returned.
+func (r Rect) Cut(a, b Vec2) Vec2 {
+	if r.Contains(b) {
+		return b
+	}
+	if !r.Contains(a) {
+		return r.Clamp(b)
+	}
+
+	// Find the first edge the line crosses
+	d := b.Sub(a)
+	t := 1.0
+	if d.X > 0 {
+		t = math.Min(t, (r.Max.X-a.X)/d.X)
+	} else if d.X < 0 {
+		t = math.Min(t, (r.Min.X-a.X)/d.X)
+	}
+	if d.Y > 0 {
+		t = math.Min(t, (r.Max.Y-a.Y)/d.Y)
+	} else if d.Y < 0 {
+		t = math.Min(t, (r.Min.Y-a.Y)/d.Y)
+	}
+	return r.Clamp(a.Add(d.Scale(t)))
+}
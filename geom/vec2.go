@@ -0,0 +1,107 @@
+// Package geom provides the 2D vector math strategies use to
+// work out where bots are and where they should go. Everything
+// is float64; the game's integer coordinates only come into
+// play when a command is sent.
+package geom
+
+import "math"
+
+// Vec2 is a point or direction on the arena.
+type Vec2 struct {
+	X, Y float64
+}
+
+// V returns the vector x,y.
+func V(x, y float64) Vec2 {
+	return Vec2{x, y}
+}
+
+// FromInt returns the vector for the game coordinates x,y.
+func FromInt(x, y int) Vec2 {
+	return Vec2{float64(x), float64(y)}
+}
+
+// Polar returns the vector of the given length pointing
+// at angle radians.
+func Polar(length, angle float64) Vec2 {
+	return Vec2{math.Cos(angle) * length, math.Sin(angle) * length}
+}
+
+// Add returns v+w.
+func (v Vec2) Add(w Vec2) Vec2 {
+	return Vec2{v.X + w.X, v.Y + w.Y}
+}
+
+// Sub returns v-w.
+func (v Vec2) Sub(w Vec2) Vec2 {
+	return Vec2{v.X - w.X, v.Y - w.Y}
+}
+
+// Scale returns v scaled by s.
+func (v Vec2) Scale(s float64) Vec2 {
+	return Vec2{v.X * s, v.Y * s}
+}
+
+// Len returns the length of v.
+func (v Vec2) Len() float64 {
+	return math.Hypot(v.X, v.Y)
+}
+
+// Dist returns the distance between v and w.
+func (v Vec2) Dist(w Vec2) float64 {
+	return w.Sub(v).Len()
+}
+
+// Norm returns v scaled to length one. The zero
+// vector stays zero.
+func (v Vec2) Norm() Vec2 {
+	l := v.Len()
+	if l == 0 {
+		return Vec2{}
+	}
+	return v.Scale(1 / l)
+}
+
+// Rotate returns v rotated by angle radians.
+func (v Vec2) Rotate(angle float64) Vec2 {
+	sin, cos := math.Sincos(angle)
+	return Vec2{v.X*cos - v.Y*sin, v.X*sin + v.Y*cos}
+}
+
+// Dot returns the dot product of v and w.
+func (v Vec2) Dot(w Vec2) float64 {
+	return v.X*w.X + v.Y*w.Y
+}
+
+// Cross returns the z component of the cross product of v
+// and w. It is positive when w is counter-clockwise of v.
+func (v Vec2) Cross(w Vec2) float64 {
+	return v.X*w.Y - v.Y*w.X
+}
+
+// Lerp returns the point t of the way from v to w.
+func (v Vec2) Lerp(w Vec2, t float64) Vec2 {
+	return v.Add(w.Sub(v).Scale(t))
+}
+
+// Angle returns the direction of v in radians.
+func (v Vec2) Angle() float64 {
+	return math.Atan2(v.Y, v.X)
+}
+
+// AngleTo returns the direction in radians of the
+// line from point v to point w.
+func (v Vec2) AngleTo(w Vec2) float64 {
+	return w.Sub(v).Angle()
+}
+
+// AngleBetween returns the signed angle in radians that
+// rotates direction v onto direction w, in [-Pi, Pi].
+func (v Vec2) AngleBetween(w Vec2) float64 {
+	return math.Atan2(v.Cross(w), v.Dot(w))
+}
+
+// Int rounds v to the game's integer coordinates.
+func (v Vec2) Int() (x, y int) {
+	return int(math.Round(v.X)), int(math.Round(v.Y))
+}
@@ -0,0 +1,3 @@
+module github.com/ScrappersIO/Player-Samples
+
+go 1.21
@@ -0,0 +1,43 @@
+package nav
+
+import (
+	"github.com/ScrappersIO/Player-Samples/geom"
+)
+
+// Follower walks one bot along a planned path, handing out
+// the next waypoint as each one is reached.
+type Follower struct {
+	Goal      geom.Vec2
+	Waypoints []geom.Vec2
+
+	// How close counts as having reached a waypoint.
+	Arrive float64
+}
+
+// NewFollower plans a path on m and returns a Follower for
+// it. If there's no way through it heads straight for goal.
+func NewFollower(m *Map, from, goal geom.Vec2, arrive float64) *Follower {
+	waypoints, ok := m.Plan(from, goal)
+	if !ok {
+		waypoints = []geom.Vec2{goal}
+	}
+	return &Follower{Goal: goal, Waypoints: waypoints, Arrive: arrive}
+}
+
+// Next returns where a bot at pos should head now, skipping
+// past any waypoints it has already reached.
+func (f *Follower) Next(pos geom.Vec2) geom.Vec2 {
+	for len(f.Waypoints) > 1 && pos.Dist(f.Waypoints[0]) <= f.Arrive {
+		f.Waypoints = f.Waypoints[1:]
+	}
+	if len(f.Waypoints) == 0 {
+		return f.Goal
+	}
+	return f.Waypoints[0]
+}
+
+// Done reports whether a bot at pos has reached the end of
+// the path.
+func (f *Follower) Done(pos geom.Vec2) bool {
+	return len(f.Waypoints) <= 1 && pos.Dist(f.Next(pos)) <= f.Arrive
+}
@@ -0,0 +1,141 @@
+// Package nav plans paths across the arena that go around
+// obstacles and, where it's worth the detour, around areas
+// marked as dangerous.
+package nav
+
+import (
+	"math"
+
+	"github.com/ScrappersIO/Player-Samples/geom"
+)
+
+// Map is a grid over the arena. Each cell is either blocked
+// or open, and open cells may carry a danger weight that
+// makes paths through them cost more.
+type Map struct {
+	Bounds geom.Rect
+	Cell   float64
+
+	// How far to keep the center of a bot from obstacles,
+	// usually half a bot.
+	Clearance float64
+
+	cols, rows int
+	blocked    []bool
+	danger     []float64
+	version    int
+}
+
+// NewMap returns an open map over bounds with square cells
+// of the given size, keeping bots clearance from obstacles.
+func NewMap(bounds geom.Rect, cell, clearance float64) *Map {
+	m := &Map{Bounds: bounds, Cell: cell, Clearance: clearance}
+	size := bounds.Size()
+	m.cols = int(math.Ceil(size.X/cell)) + 1
+	m.rows = int(math.Ceil(size.Y/cell)) + 1
+	m.blocked = make([]bool, m.cols*m.rows)
+	m.danger = make([]float64, m.cols*m.rows)
+	return m
+}
+
+// Version changes every time the map does, so anything
+// planned on an older version knows to plan again.
+func (m *Map) Version() int {
+	return m.version
+}
+
+// BlockCircle marks a round obstacle.
+func (m *Map) BlockCircle(center geom.Vec2, radius float64) {
+	reach := radius + m.Clearance
+	m.each(func(i int, p geom.Vec2) {
+		if p.Dist(center) <= reach {
+			m.blocked[i] = true
+		}
+	})
+}
+
+// BlockRect marks a rectangular obstacle.
+func (m *Map) BlockRect(r geom.Rect) {
+	grown := r.Inset(-m.Clearance)
+	m.each(func(i int, p geom.Vec2) {
+		if grown.Contains(p) {
+			m.blocked[i] = true
+		}
+	})
+}
+
+// AddDanger marks a round area as dangerous. The danger is
+// weight at the center and fades to nothing at radius. A
+// weight of one makes crossing the center cost twice as much
+// as crossing open ground.
+func (m *Map) AddDanger(center geom.Vec2, radius, weight float64) {
+	m.each(func(i int, p geom.Vec2) {
+		d := p.Dist(center)
+		if d < radius {
+			m.danger[i] += weight * (1 - d/radius)
+		}
+	})
+}
+
+// SetDanger replaces all danger with whatever f says it is
+// at the center of each cell.
+func (m *Map) SetDanger(f func(p geom.Vec2) float64) {
+	m.each(func(i int, p geom.Vec2) {
+		m.danger[i] = math.Max(0, f(p))
+	})
+}
+
+// ClearDanger forgets all danger, leaving obstacles alone.
+func (m *Map) ClearDanger() {
+	m.each(func(i int, p geom.Vec2) {
+		m.danger[i] = 0
+	})
+}
+
+// Blocked reports whether p is inside an obstacle (or
+// too close to one for a bot to fit).
+func (m *Map) Blocked(p geom.Vec2) bool {
+	i, ok := m.index(p)
+	return !ok || m.blocked[i]
+}
+
+// Danger returns the danger weight at p.
+func (m *Map) Danger(p geom.Vec2) float64 {
+	i, ok := m.index(p)
+	if !ok {
+		return 0
+	}
+	return m.danger[i]
+}
+
+// each calls f with the index and center of every cell,
+// and counts as a change to the map.
+func (m *Map) each(f func(i int, p geom.Vec2)) {
+	for row := 0; row < m.rows; row++ {
+		for col := 0; col < m.cols; col++ {
+			f(row*m.cols+col, m.center(col, row))
+		}
+	}
+	m.version++
+}
+
+// center returns the middle of a cell.
+func (m *Map) center(col, row int) geom.Vec2 {
+	return m.Bounds.Min.Add(geom.V(float64(col)*m.Cell, float64(row)*m.Cell))
+}
+
+// cellOf returns the cell containing p.
+func (m *Map) cellOf(p geom.Vec2) (col, row int) {
+	rel := p.Sub(m.Bounds.Min)
+	return int(math.Round(rel.X / m.Cell)), int(math.Round(rel.Y / m.Cell))
+}
+
+// index returns the index of the cell containing p, and
+// false if p is off the map.
+func (m *Map) index(p geom.Vec2) (int, bool) {
+	col, row := m.cellOf(p)
+	if col < 0 || row < 0 || col >= m.cols || row >= m.rows {
+		return 0, false
+	}
+	return row*m.cols + col, true
+}
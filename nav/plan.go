@@ -0,0 +1,197 @@
+package nav
+
+import (
+	"container/heap"
+	"math"
+
+	"github.com/ScrappersIO/Player-Samples/geom"
+)
+
+// The eight directions out of a cell.
+var steps = [8][2]int{
+	{1, 0}, {-1, 0}, {0, 1}, {0, -1},
+	{1, 1}, {1, -1}, {-1, 1}, {-1, -1},
+}
+
+// Plan finds the cheapest path from one point to another
+// with A*. The waypoints it returns don't include from and
+// end at to (or, if to is inside an obstacle, the nearest
+// open spot). ok is false if there is no way through.
+func (m *Map) Plan(from, to geom.Vec2) (waypoints []geom.Vec2, ok bool) {
+
+	start, startOK := m.index(m.Bounds.Clamp(from))
+	goal, goalOK := m.index(m.Bounds.Clamp(to))
+	if !startOK || !goalOK {
+		return nil, false
+	}
+	if m.blocked[goal] {
+		goal, ok = m.nearestOpen(goal)
+		if !ok {
+			return nil, false
+		}
+		to = m.center(goal%m.cols, goal/m.cols)
+	}
+
+	// Standard A*. A bot stuck inside an obstacle is still
+	// allowed to plan its way out.
+	cost := make([]float64, len(m.blocked))
+	came := make([]int, len(m.blocked))
+	for i := range cost {
+		cost[i] = math.Inf(1)
+		came[i] = -1
+	}
+	goalPos := m.center(goal%m.cols, goal/m.cols)
+	cost[start] = 0
+	open := &queue{}
+	heap.Push(open, item{start, m.center(start%m.cols, start/m.cols).Dist(goalPos)})
+
+	for open.Len() > 0 {
+		cur := heap.Pop(open).(item)
+		if cur.cell == goal {
+			break
+		}
+		col, row := cur.cell%m.cols, cur.cell/m.cols
+		for _, step := range steps {
+			ncol, nrow := col+step[0], row+step[1]
+			if ncol < 0 || nrow < 0 || ncol >= m.cols || nrow >= m.rows {
+				continue
+			}
+			next := nrow*m.cols + ncol
+			if m.blocked[next] {
+				continue
+			}
+
+			// Don't cut corners past obstacles
+			if step[0] != 0 && step[1] != 0 &&
+				(m.blocked[row*m.cols+ncol] || m.blocked[nrow*m.cols+col]) {
+				continue
+			}
+
+			length := m.Cell * math.Hypot(float64(step[0]), float64(step[1]))
+			c := cost[cur.cell] + length*(1+m.danger[next])
+			if c < cost[next] {
+				cost[next] = c
+				came[next] = cur.cell
+				est := c + m.center(ncol, nrow).Dist(goalPos)
+				heap.Push(open, item{next, est})
+			}
+		}
+	}
+	if math.IsInf(cost[goal], 1) {
+		return nil, false
+	}
+
+	// Walk back from the goal
+	cells := make([]int, 0)
+	for c := goal; c != -1; c = came[c] {
+		cells = append(cells, c)
+	}
+	if len(cells) == 1 {
+		return []geom.Vec2{to}, true
+	}
+	path := make([]geom.Vec2, len(cells))
+	for i, c := range cells {
+		path[len(cells)-1-i] = m.center(c%m.cols, c/m.cols)
+	}
+	path[0] = from
+	path[len(path)-1] = to
+
+	return m.smooth(path), true
+}
+
+// smooth drops waypoints that a straight line can skip
+// without hitting an obstacle or costing more than going
+// the long way round.
+func (m *Map) smooth(path []geom.Vec2) []geom.Vec2 {
+	out := make([]geom.Vec2, 0)
+	i := 0
+	for i < len(path)-1 {
+		next := i + 1
+		detour := m.lineCost(path[i], path[i+1])
+		for j := i + 2; j < len(path); j++ {
+			detour += m.lineCost(path[j-1], path[j])
+			direct, clear := m.lineCostClear(path[i], path[j])
+			if clear && direct <= detour+1e-9 {
+				next = j
+			}
+		}
+		out = append(out, path[next])
+		i = next
+	}
+	return out
+}
+
+// lineCost is lineCostClear ignoring obstacles.
+func (m *Map) lineCost(a, b geom.Vec2) float64 {
+	c, _ := m.lineCostClear(a, b)
+	return c
+}
+
+// lineCostClear returns what it costs to go straight from a
+// to b, and whether the way is clear of obstacles.
+func (m *Map) lineCostClear(a, b geom.Vec2) (float64, bool) {
+	length := a.Dist(b)
+	samples := int(math.Ceil(length/(m.Cell/2))) + 1
+	cost := 0.0
+	clear := true
+	for s := 0; s < samples; s++ {
+		p := a.Lerp(b, (float64(s)+0.5)/float64(samples))
+		i, ok := m.index(p)
+		if !ok {
+			continue
+		}
+		if m.blocked[i] && s > 0 {
+			clear = false
+		}
+		cost += length / float64(samples) * (1 + m.danger[i])
+	}
+	return cost, clear
+}
+
+// nearestOpen finds the open cell closest to a blocked one.
+func (m *Map) nearestOpen(cell int) (int, bool) {
+	seen := make([]bool, len(m.blocked))
+	todo := []int{cell}
+	seen[cell] = true
+	for len(todo) > 0 {
+		c := todo[0]
+		todo = todo[1:]
+		if !m.blocked[c] {
+			return c, true
+		}
+		col, row := c%m.cols, c/m.cols
+		for _, step := range steps[:4] {
+			ncol, nrow := col+step[0], row+step[1]
+			if ncol < 0 || nrow < 0 || ncol >= m.cols || nrow >= m.rows {
+				continue
+			}
+			next := nrow*m.cols + ncol
+			if !seen[next] {
+				seen[next] = true
+				todo = append(todo, next)
+			}
+		}
+	}
+	return 0, false
+}
+
+// item is a cell waiting to be explored, with its
+// estimated total cost.
+type item struct {
+	cell int
+	est  float64
+}
+
+// queue is a priority queue of cells, cheapest first.
+type queue []item
+
+func (q queue) Len() int            { return len(q) }
+func (q queue) Less(i, j int) bool  { return q[i].est < q[j].est }
+func (q queue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *queue) Push(x interface{}) { *q = append(*q, x.(item)) }
+func (q *queue) Pop() interface{} {
+	old := *q
+	it := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return it
+}
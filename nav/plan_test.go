@@ -0,0 +1,125 @@
+package nav
+
+import (
+	"testing"
+
+	"github.com/ScrappersIO/Player-Samples/geom"
+)
+
+func TestPlan(t *testing.T) {
+	tests := []struct {
+		name     string
+		setup    func(m *Map)
+		from, to geom.Vec2
+		ok       bool
+		end      geom.Vec2 // Where the path should finish
+		avoid    geom.Vec2 // A point the path should keep away from...
+		avoidBy  float64   // ...by at least this much
+	}{
+		{
+			name: "open ground",
+			from: geom.V(0, 0), to: geom.V(100, 100),
+			ok: true, end: geom.V(100, 100),
+		},
+		{
+			name: "same cell",
+			from: geom.V(50, 50), to: geom.V(52, 51),
+			ok: true, end: geom.V(52, 51),
+		},
+		{
+			name: "around a wall",
+			setup: func(m *Map) {
+				m.BlockRect(geom.R(40, 0, 60, 80))
+			},
+			from: geom.V(10, 10), to: geom.V(90, 10),
+			ok: true, end: geom.V(90, 10),
+		},
+		{
+			name: "no way through",
+			setup: func(m *Map) {
+				m.BlockRect(geom.R(40, -10, 60, 110))
+			},
+			from: geom.V(10, 10), to: geom.V(90, 10),
+			ok: false,
+		},
+		{
+			name: "goal inside an obstacle",
+			setup: func(m *Map) {
+				m.BlockCircle(geom.V(80, 50), 15)
+			},
+			from: geom.V(10, 50), to: geom.V(80, 50),
+			ok: true,
+		},
+		{
+			name: "around danger",
+			setup: func(m *Map) {
+				m.AddDanger(geom.V(50, 50), 30, 20)
+			},
+			from: geom.V(0, 50), to: geom.V(100, 50),
+			ok: true, end: geom.V(100, 50),
+			avoid: geom.V(50, 50), avoidBy: 15,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMap(geom.R(0, 0, 100, 100), 5, 0)
+			if tt.setup != nil {
+				tt.setup(m)
+			}
+			waypoints, ok := m.Plan(tt.from, tt.to)
+			if ok != tt.ok {
+				t.Fatalf("ok = %v, want %v", ok, tt.ok)
+			}
+			if !ok {
+				return
+			}
+			if len(waypoints) == 0 {
+				t.Fatal("no waypoints")
+			}
+			last := waypoints[len(waypoints)-1]
+			if tt.end != (geom.Vec2{}) && last.Dist(tt.end) > 1e-9 {
+				t.Errorf("ends at %v, want %v", last, tt.end)
+			}
+
+			// Walk the path, checking it stays clear
+			at := tt.from
+			for _, w := range waypoints {
+				for s := 0.0; s <= 1; s += 0.01 {
+					p := at.Lerp(w, s)
+					if m.Blocked(p) {
+						t.Fatalf("path %v goes through an obstacle at %v", waypoints, p)
+					}
+					if tt.avoidBy > 0 && p.Dist(tt.avoid) < tt.avoidBy {
+						t.Fatalf("path %v comes within %v of %v", waypoints, p.Dist(tt.avoid), tt.avoid)
+					}
+				}
+				at = w
+			}
+		})
+	}
+}
+
+func TestFollower(t *testing.T) {
+	m := NewMap(geom.R(0, 0, 100, 100), 5, 0)
+	m.BlockRect(geom.R(40, 0, 60, 80))
+	f := NewFollower(m, geom.V(10, 10), geom.V(90, 10), 2)
+	if len(f.Waypoints) < 2 {
+		t.Fatalf("expected a detour, got %v", f.Waypoints)
+	}
+
+	// Reaching each waypoint moves on to the next
+	pos := geom.V(10, 10)
+	for i := 0; i < 100 && !f.Done(pos); i++ {
+		pos = f.Next(pos)
+	}
+	if !f.Done(pos) || pos.Dist(geom.V(90, 10)) > 1e-9 {
+		t.Errorf("stopped at %v, want %v", pos, geom.V(90, 10))
+	}
+
+	// No way through heads straight for the goal
+	m.BlockRect(geom.R(40, -10, 60, 110))
+	f = NewFollower(m, geom.V(10, 10), geom.V(90, 10), 2)
+	if next := f.Next(geom.V(10, 10)); next != geom.V(90, 10) {
+		t.Errorf("Next = %v, want the goal", next)
+	}
+}
@@ -0,0 +1,252 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"sort"
+
+	"github.com/ScrappersIO/Player-Samples/client"
+)
+
+// MATCH CHECK
+// Reads recorded matches and flags protocol problems made
+// by us or by the game. Exits with 1 if anything was found
+// so it can gate tests.
+
+// BotKey identifies a bot across all players.
+type BotKey struct {
+	PID, BID int
+}
+
+// Problem is a single protocol violation.
+type Problem struct {
+	T    int64  // Milliseconds into the recording
+	Side string // client.Out for us, client.In for the game
+	Kind string
+	Text string
+}
+
+// reporter records a problem.
+type reporter func(t int64, side, kind, format string, args ...interface{})
+
+// bounds is the rectangle bots were seen in.
+type bounds struct {
+	minX, minY, maxX, maxY int
+	seen                   bool
+}
+
+// add grows the bounds to include x,y.
+func (b *bounds) add(x, y int) {
+	if !b.seen {
+		b.minX, b.maxX, b.minY, b.maxY = x, x, y, y
+		b.seen = true
+		return
+	}
+	b.minX = min(b.minX, x)
+	b.maxX = max(b.maxX, x)
+	b.minY = min(b.minY, y)
+	b.maxY = max(b.maxY, y)
+}
+
+// contains reports whether x,y is within margin of the bounds.
+func (b *bounds) contains(x, y, margin int) bool {
+	return x >= b.minX-margin && x <= b.maxX+margin &&
+		y >= b.minY-margin && y <= b.maxY+margin
+}
+
+func main() {
+
+	var margin int
+	flag.IntVar(&margin, "margin", 1000, "How far outside the area bots were seen a MOVE may go before it's absurd.")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %v [flags] recording...\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	// Check every recording and tally up the problems
+	total := 0
+	kinds := make(map[string]int)
+	for _, path := range flag.Args() {
+		entries, err := client.LoadRecording(path)
+		if err != nil {
+			log.Fatalf("Failed to load recording %v: %v\n", path, err)
+		}
+		for _, p := range check(entries, margin) {
+			fmt.Printf("%v: %.3fs %v %v: %v\n", path, float64(p.T)/1000, p.Side, p.Kind, p.Text)
+			kinds[p.Kind]++
+			total++
+		}
+	}
+
+	if total == 0 {
+		fmt.Println("No problems found.")
+		return
+	}
+
+	fmt.Printf("\n%v problems found:\n", total)
+	names := make([]string, 0, len(kinds))
+	for kind := range kinds {
+		names = append(names, kind)
+	}
+	sort.Strings(names)
+	for _, kind := range names {
+		fmt.Printf("  %-16v %v\n", kind, kinds[kind])
+	}
+	os.Exit(1)
+}
+
+// check replays a recording and returns its problems.
+func check(entries []client.RecordEntry, margin int) []Problem {
+
+	problems := make([]Problem, 0)
+	var report reporter = func(t int64, side, kind, format string, args ...interface{}) {
+		problems = append(problems, Problem{t, side, kind, fmt.Sprintf(format, args...)})
+	}
+
+	// The area bots are seen in decides what counts as an
+	// absurd MOVE, so find it before anything else.
+	var area bounds
+	for _, entry := range entries {
+		decoded, err := entry.Decode()
+		if err != nil {
+			continue
+		}
+		switch msg := decoded.(type) {
+		case *client.ReadyMsg:
+			for _, bot := range msg.Bots {
+				area.add(bot.X, bot.Y)
+			}
+		case *client.BotMsg:
+			area.add(msg.X, msg.Y)
+		}
+	}
+
+	var pid int
+	ready := false
+	allDead := false
+	afterDeath := 0
+	players := make(map[int]bool)
+	alive := make(map[BotKey]bool)
+	ours := make(map[int]bool)
+
+	for _, entry := range entries {
+
+		decoded, err := entry.Decode()
+		if err != nil {
+			report(entry.T, entry.Dir, "undecodable", "%v", err)
+			continue
+		}
+
+		// Once our last bot is gone the match is over for us.
+		// Count what still comes through and report it once.
+		if allDead {
+			afterDeath++
+			if afterDeath == 1 {
+				report(entry.T, entry.Dir, "after-death", "message after our last bot died")
+			}
+		}
+
+		switch msg := decoded.(type) {
+
+		case *client.ReadyMsg:
+			if ready {
+				report(entry.T, client.In, "ready-repeated", "second READY message")
+			}
+			ready = true
+			pid = msg.PID
+			for _, bot := range msg.Bots {
+				players[bot.PID] = true
+				alive[BotKey{bot.PID, bot.BID}] = bot.Health > 0
+				if bot.PID == pid {
+					ours[bot.BID] = true
+				}
+			}
+
+		case *client.BotMsg:
+			key := BotKey{msg.PID, msg.BID}
+			if !ready {
+				report(entry.T, client.In, "before-ready", "BOT for PID %v BID %v before READY", msg.PID, msg.BID)
+			} else if !players[msg.PID] {
+				report(entry.T, client.In, "unknown-player", "BOT for PID %v, which wasn't in READY", msg.PID)
+			}
+			alive[key] = msg.Health > 0
+
+			// Did that just kill our last bot?
+			if msg.PID == pid && msg.Health <= 0 && !allDead {
+				allDead = true
+				for bid := range ours {
+					if alive[BotKey{pid, bid}] {
+						allDead = false
+					}
+				}
+			}
+
+		case *client.Command:
+			checkCommand(msg, entry.T, report, pid, ours, alive, &area, margin)
+
+		case *client.Msg:
+			report(entry.T, client.In, "unknown-type", "message of unknown type %q", msg.Type)
+		}
+	}
+
+	if afterDeath > 1 {
+		last := entries[len(entries)-1]
+		report(last.T, "ALL", "after-death", "%v messages in all after our last bot died", afterDeath)
+	}
+
+	return problems
+}
+
+// checkCommand reports problems with one of our commands.
+func checkCommand(cmd *client.Command, t int64, report reporter,
+	pid int, ours map[int]bool, alive map[BotKey]bool, area *bounds, margin int) {
+
+	// Every command is for one of our live bots
+	if !ours[cmd.BID] {
+		report(t, client.Out, "not-ours", "%v for BID %v, which isn't ours", cmd.Cmd, cmd.BID)
+	} else if !alive[BotKey{pid, cmd.BID}] {
+		report(t, client.Out, "dead-bot", "%v for BID %v, which is dead", cmd.Cmd, cmd.BID)
+	}
+
+	switch cmd.Cmd {
+
+	case "POWER":
+		sum := cmd.FPow + cmd.MPow + cmd.SPow
+		if sum > client.MaxPow {
+			report(t, client.Out, "over-power", "POWER for BID %v sums to %v, over MaxPow %v", cmd.BID, sum, client.MaxPow)
+		}
+		if cmd.FPow < 0 || cmd.MPow < 0 || cmd.SPow < 0 {
+			report(t, client.Out, "negative-power", "POWER for BID %v is %v/%v/%v", cmd.BID, cmd.FPow, cmd.MPow, cmd.SPow)
+		}
+
+	case "TARGET":
+		target := BotKey{cmd.TPID, cmd.TBID}
+		targetAlive, known := alive[target]
+		if cmd.TPID == pid {
+			report(t, client.Out, "target-own", "BID %v targets our own BID %v", cmd.BID, cmd.TBID)
+		} else if !known {
+			report(t, client.Out, "target-unknown", "BID %v targets unknown PID %v BID %v", cmd.BID, cmd.TPID, cmd.TBID)
+		} else if !targetAlive {
+			report(t, client.Out, "target-dead", "BID %v targets dead PID %v BID %v", cmd.BID, cmd.TPID, cmd.TBID)
+		}
+
+	case "MOVE":
+		if area.seen && !area.contains(cmd.X, cmd.Y, margin) {
+			dist := math.Max(
+				math.Max(float64(area.minX-cmd.X), float64(cmd.X-area.maxX)),
+				math.Max(float64(area.minY-cmd.Y), float64(cmd.Y-area.maxY)))
+			report(t, client.Out, "absurd-move", "MOVE for BID %v to %v,%v is %.0f outside where bots have been", cmd.BID, cmd.X, cmd.Y, dist)
+		}
+
+	default:
+		report(t, client.Out, "unknown-command", "unknown command %q for BID %v", cmd.Cmd, cmd.BID)
+	}
+}
@@ -0,0 +1,294 @@
+package main
+
+import (
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"log"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"github.com/ScrappersIO/Player-Samples/client"
+)
+
+// MATCH HEATMAP
+// Aggregates recorded matches into heatmaps of where bots
+// spent their time, where they died and where their shots
+// landed. Each map is written as a PNG and a CSV grid.
+
+// The maps we draw for each group of bots.
+var layers = []string{"time", "deaths", "hits"}
+
+// BotKey identifies a bot across all players.
+type BotKey struct {
+	PID, BID int
+}
+
+// point is something that happened at a spot, with a
+// weight (seconds for time, one for everything else).
+type point struct {
+	group  string
+	layer  string
+	x, y   int
+	weight float64
+}
+
+// grid is a heatmap over the area everything happened in.
+type grid struct {
+	minX, minY int
+	cell       int
+	cols, rows int
+	cells      []float64
+}
+
+func main() {
+
+	var outDir, by string
+	var cell, scale int
+	flag.StringVar(&outDir, "out", ".", "Directory to write the heatmaps to.")
+	flag.StringVar(&by, "by", "side", "Group bots by side (us or them) or by pid.")
+	flag.IntVar(&cell, "cell", int(client.BotDiam), "Size of a heatmap cell in game units.")
+	flag.IntVar(&scale, "scale", 4, "Pixels per cell in the PNGs.")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %v [flags] recording...\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 || (by != "side" && by != "pid") || cell <= 0 || scale <= 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	// Gather everything from every recording
+	points := make([]point, 0)
+	for _, path := range flag.Args() {
+		entries, err := client.LoadRecording(path)
+		if err != nil {
+			log.Fatalf("Failed to load recording %v: %v\n", path, err)
+		}
+		points = append(points, collect(entries, by)...)
+	}
+	if len(points) == 0 {
+		log.Fatalf("No bots found in the recordings.\n")
+	}
+
+	// All maps share the same grid so they line up
+	minX, minY := math.MaxInt, math.MaxInt
+	maxX, maxY := math.MinInt, math.MinInt
+	groups := make(map[string]bool)
+	for _, p := range points {
+		minX, maxX = min(minX, p.x), max(maxX, p.x)
+		minY, maxY = min(minY, p.y), max(maxY, p.y)
+		groups[p.group] = true
+	}
+	minX = floorTo(minX, cell)
+	minY = floorTo(minY, cell)
+	cols := (maxX-minX)/cell + 1
+	rows := (maxY-minY)/cell + 1
+
+	names := make([]string, 0, len(groups))
+	for group := range groups {
+		names = append(names, group)
+	}
+	sort.Strings(names)
+
+	for _, group := range names {
+		for _, layer := range layers {
+
+			g := &grid{minX, minY, cell, cols, rows, make([]float64, cols*rows)}
+			for _, p := range points {
+				if p.group == group && p.layer == layer {
+					g.add(p.x, p.y, p.weight)
+				}
+			}
+
+			base := filepath.Join(outDir, group+"-"+layer)
+			err := g.writeCSV(base + ".csv")
+			if err != nil {
+				log.Fatalf("Failed to write %v.csv: %v\n", base, err)
+			}
+			err = g.writePNG(base+".png", scale)
+			if err != nil {
+				log.Fatalf("Failed to write %v.png: %v\n", base, err)
+			}
+			fmt.Printf("Wrote %v.csv and %v.png\n", base, base)
+		}
+	}
+}
+
+// collect replays a recording and returns where bots were,
+// died and hit. by decides how bots are grouped.
+func collect(entries []client.RecordEntry, by string) []point {
+
+	points := make([]point, 0)
+	bots := make(map[BotKey]client.BotMsg)
+	seen := make(map[BotKey]int64)
+	var pid int
+	var last int64
+
+	groupOf := func(botPID int) string {
+		if by == "pid" {
+			return "pid" + strconv.Itoa(botPID)
+		}
+		if botPID == pid {
+			return "us"
+		}
+		return "them"
+	}
+
+	// A bot spent the time since we last heard from it
+	// where it was then.
+	spent := func(key BotKey, t int64) {
+		bot, known := bots[key]
+		if known && bot.Health > 0 && t > seen[key] {
+			dt := float64(t-seen[key]) / 1000
+			points = append(points, point{groupOf(bot.PID), "time", bot.X, bot.Y, dt})
+		}
+		seen[key] = t
+	}
+
+	for _, entry := range entries {
+		decoded, err := entry.Decode()
+		if err != nil {
+			continue
+		}
+		last = max(last, entry.T)
+
+		switch msg := decoded.(type) {
+
+		case *client.ReadyMsg:
+			pid = msg.PID
+			for _, bot := range msg.Bots {
+				key := BotKey{bot.PID, bot.BID}
+				bots[key] = bot
+				seen[key] = entry.T
+			}
+
+		case *client.BotMsg:
+			key := BotKey{msg.PID, msg.BID}
+			spent(key, entry.T)
+			group := groupOf(msg.PID)
+			if msg.Fired {
+				points = append(points, point{group, "hits", msg.HitX, msg.HitY, 1})
+			}
+			if msg.Health <= 0 && bots[key].Health > 0 {
+				points = append(points, point{group, "deaths", msg.X, msg.Y, 1})
+			}
+			bots[key] = *msg
+		}
+	}
+
+	// Survivors were somewhere until the end
+	for key := range bots {
+		spent(key, last)
+	}
+
+	return points
+}
+
+// add adds weight to the cell containing x,y.
+func (g *grid) add(x, y int, weight float64) {
+	col := (x - g.minX) / g.cell
+	row := (y - g.minY) / g.cell
+	g.cells[row*g.cols+col] += weight
+}
+
+// writeCSV writes the grid with the game coordinates of
+// each cell's corner along the top and left.
+func (g *grid) writeCSV(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	w := csv.NewWriter(file)
+	header := []string{"y\\x"}
+	for col := 0; col < g.cols; col++ {
+		header = append(header, strconv.Itoa(g.minX+col*g.cell))
+	}
+	w.Write(header)
+	for row := 0; row < g.rows; row++ {
+		record := []string{strconv.Itoa(g.minY + row*g.cell)}
+		for col := 0; col < g.cols; col++ {
+			record = append(record, strconv.FormatFloat(g.cells[row*g.cols+col], 'g', 4, 64))
+		}
+		w.Write(record)
+	}
+	w.Flush()
+	if err = w.Error(); err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+// writePNG draws the grid with scale pixels per cell.
+func (g *grid) writePNG(path string, scale int) error {
+
+	// Square roots keep a few hot cells from washing
+	// out everything else.
+	peak := 0.0
+	for _, v := range g.cells {
+		peak = math.Max(peak, v)
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, g.cols*scale, g.rows*scale))
+	for row := 0; row < g.rows; row++ {
+		for col := 0; col < g.cols; col++ {
+			heat := 0.0
+			if peak > 0 {
+				heat = math.Sqrt(g.cells[row*g.cols+col] / peak)
+			}
+			c := heatColor(heat)
+			for py := row * scale; py < (row+1)*scale; py++ {
+				for px := col * scale; px < (col+1)*scale; px++ {
+					img.Set(px, py, c)
+				}
+			}
+		}
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	err = png.Encode(file, img)
+	if err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+// heatColor maps 0..1 to black, blue, red, yellow, white.
+func heatColor(heat float64) color.RGBA {
+	stops := []color.RGBA{
+		{0, 0, 0, 255},
+		{0, 0, 200, 255},
+		{220, 0, 0, 255},
+		{255, 220, 0, 255},
+		{255, 255, 255, 255},
+	}
+	pos := heat * float64(len(stops)-1)
+	i := min(int(pos), len(stops)-2)
+	f := pos - float64(i)
+	a, b := stops[i], stops[i+1]
+	mix := func(x, y uint8) uint8 {
+		return uint8(float64(x) + (float64(y)-float64(x))*f)
+	}
+	return color.RGBA{mix(a.R, b.R), mix(a.G, b.G), mix(a.B, b.B), 255}
+}
+
+// floorTo rounds n down to a multiple of step.
+func floorTo(n, step int) int {
+	if n < 0 {
+		return -((-n + step - 1) / step * step)
+	}
+	return n / step * step
+}
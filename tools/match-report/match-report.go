@@ -0,0 +1,385 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ScrappersIO/Player-Samples/client"
+	"github.com/ScrappersIO/Player-Samples/geom"
+)
+
+// MATCH REPORT
+// Reads a match recorded with a player's -record flag and
+// reports damage, accuracy, kills, time spent in range of
+// the target and the power allocations we made.
+
+// BotKey identifies a bot across all players.
+type BotKey struct {
+	PID, BID int
+}
+
+// Report is everything we learned from a match. Times are
+// seconds since the READY message.
+type Report struct {
+	PID          int
+	Duration     float64
+	Range        float64
+	FirstKill    float64 // -1 if nobody died
+	Unattributed int     // Damage we couldn't pin on a shooter
+	Players      []PlayerStats
+	Bots         []BotStats
+	Power        []PowerChange
+}
+
+// PlayerStats totals the stats of all bots owned by a player.
+type PlayerStats struct {
+	PID         int
+	DamageDealt int
+	DamageTaken int
+	Shots       int
+	Hits        int
+	HitRate     float64
+	Kills       int
+	FirstKill   float64 // -1 if no kills
+	BotsLeft    int
+}
+
+// BotStats are the stats of a single bot.
+type BotStats struct {
+	PID, BID    int
+	DamageDealt int
+	DamageTaken int
+	Shots       int
+	Hits        int
+	HitRate     float64
+	Kills       int
+	Died        float64 // -1 if it survived
+	InRange     float64 // Only known for our bots
+}
+
+// PowerChange is a POWER command we sent.
+type PowerChange struct {
+	T                float64
+	BID              int
+	FPow, MPow, SPow int
+}
+
+// shot is a bot firing at a spot.
+type shot struct {
+	t       int64
+	shooter BotKey
+	from    geom.Vec2
+	at      geom.Vec2
+	hit     bool
+}
+
+// rangeSample is a stretch of time one of our bots
+// spent at a distance from its target.
+type rangeSample struct {
+	bid  int
+	dt   int64
+	dist float64
+}
+
+func main() {
+
+	var asJSON bool
+	var weaponRange float64
+	flag.BoolVar(&asJSON, "json", false, "Output the report as JSON.")
+	flag.Float64Var(&weaponRange, "range", 0, "Weapon range used for time in range. Zero infers it from the longest hit.")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %v [flags] recording\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	entries, err := client.LoadRecording(flag.Arg(0))
+	if err != nil {
+		log.Fatalf("Failed to load recording: %v\n", err)
+	}
+
+	report := analyze(entries, weaponRange)
+
+	if asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		enc.Encode(report)
+		return
+	}
+	printReport(os.Stdout, report)
+}
+
+// analyze replays the recording and builds the report.
+func analyze(entries []client.RecordEntry, weaponRange float64) Report {
+
+	report := Report{}
+	report.FirstKill = -1
+
+	bots := make(map[BotKey]*client.BotMsg)
+	stats := make(map[BotKey]*BotStats)
+	targets := make(map[int]BotKey)
+	lastPower := make(map[int]client.Command)
+	shots := make([]*shot, 0)
+	matcher := client.NewHitMatcher()
+	firstKills := make(map[int]float64)
+	dropped := 0
+	samples := make([]rangeSample, 0)
+	var start, last int64
+	started := false
+
+	// Stats are created the first time we hear of a bot
+	botStats := func(key BotKey) *BotStats {
+		s, ok := stats[key]
+		if !ok {
+			s = &BotStats{PID: key.PID, BID: key.BID, Died: -1}
+			stats[key] = s
+		}
+		return s
+	}
+
+	seconds := func(t int64) float64 {
+		return float64(t-start) / 1000
+	}
+
+	// Credit the shooter with each hit the matcher pairs up
+	// from the shots and health drops we feed it.
+	credit := func(h client.Hit, ok bool) {
+		if !ok {
+			return
+		}
+		s := botStats(BotKey(h.Shooter))
+		s.DamageDealt += h.Damage
+		if h.Fatal {
+			s.Kills++
+
+			// Hits aren't always matched in time order
+			t := seconds(h.Time.UnixMilli())
+			if first, ok := firstKills[h.Shooter.PID]; !ok || t < first {
+				firstKills[h.Shooter.PID] = t
+			}
+		}
+	}
+
+	for _, entry := range entries {
+
+		decoded, err := entry.Decode()
+		if err != nil {
+			log.Printf("Skipping undecodable message at %vms: %v\n", entry.T, err)
+			continue
+		}
+
+		// Note how far each of our bots was from its target
+		// for the time since the last message.
+		if started && entry.T > last {
+			for bid, key := range targets {
+				me, ok := bots[BotKey{report.PID, bid}]
+				them, ok2 := bots[key]
+				if ok && ok2 && me.Health > 0 && them.Health > 0 {
+					dist := me.Pos().Dist(them.Pos())
+					samples = append(samples, rangeSample{bid, entry.T - last, dist})
+				}
+			}
+		}
+		if entry.T > last {
+			last = entry.T
+		}
+
+		switch msg := decoded.(type) {
+
+		// The match starts with READY
+		case *client.ReadyMsg:
+			report.PID = msg.PID
+			start = entry.T
+			started = true
+			for i := range msg.Bots {
+				bot := msg.Bots[i]
+				key := BotKey{bot.PID, bot.BID}
+				bots[key] = &bot
+				botStats(key)
+			}
+
+		// Bot updates tell us about shots, damage and deaths
+		case *client.BotMsg:
+			key := BotKey{msg.PID, msg.BID}
+			s := botStats(key)
+
+			if msg.Fired {
+				s.Shots++
+				sh := &shot{t: entry.T, shooter: key, from: msg.Pos(), at: msg.HitPos()}
+				for otherKey, other := range bots {
+					if otherKey.PID != key.PID && other.Health > 0 &&
+						other.Pos().Dist(sh.at) <= client.BotDiam/2 {
+						sh.hit = true
+						s.Hits++
+						break
+					}
+				}
+				shots = append(shots, sh)
+				credit(matcher.Shot(time.UnixMilli(entry.T), client.BotRef(key), sh.from, sh.at))
+			}
+
+			prev, ok := bots[key]
+			if ok && prev.Health > 0 && msg.Health < prev.Health {
+				amount := prev.Health - max(msg.Health, 0)
+				fatal := msg.Health <= 0
+				dropped += amount
+				s.DamageTaken += amount
+				credit(matcher.Drop(time.UnixMilli(entry.T), client.BotRef(key), msg.Pos(), amount, fatal))
+				if fatal {
+					s.Died = seconds(entry.T)
+					if report.FirstKill < 0 {
+						report.FirstKill = s.Died
+					}
+				}
+			}
+
+			bot := *msg
+			bots[key] = &bot
+
+		// Our commands tell us targets and power
+		case *client.Command:
+			switch msg.Cmd {
+			case "TARGET":
+				targets[msg.BID] = BotKey{msg.TPID, msg.TBID}
+			case "POWER":
+				prev, ok := lastPower[msg.BID]
+				if ok && prev == *msg {
+					continue
+				}
+				lastPower[msg.BID] = *msg
+				change := PowerChange{seconds(entry.T), msg.BID, msg.FPow, msg.MPow, msg.SPow}
+				report.Power = append(report.Power, change)
+			}
+		}
+	}
+	report.Duration = seconds(last)
+
+	// Whatever damage the matcher couldn't pin on a shot
+	attributed := 0
+	for _, s := range stats {
+		attributed += s.DamageDealt
+	}
+	report.Unattributed = dropped - attributed
+
+	// If we weren't told the weapon range, the longest
+	// hit is the best guess we have.
+	if weaponRange <= 0 {
+		for _, sh := range shots {
+			if sh.hit {
+				weaponRange = math.Max(weaponRange, sh.from.Dist(sh.at))
+			}
+		}
+	}
+	report.Range = weaponRange
+	for _, sample := range samples {
+		if sample.dist <= weaponRange {
+			stats[BotKey{report.PID, sample.bid}].InRange += float64(sample.dt) / 1000
+		}
+	}
+
+	// Roll bot stats up into player stats
+	players := make(map[int]*PlayerStats)
+	for key, s := range stats {
+		if s.Shots > 0 {
+			s.HitRate = float64(s.Hits) / float64(s.Shots)
+		}
+		p, ok := players[key.PID]
+		if !ok {
+			p = &PlayerStats{PID: key.PID, FirstKill: -1}
+			players[key.PID] = p
+		}
+		p.DamageDealt += s.DamageDealt
+		p.DamageTaken += s.DamageTaken
+		p.Shots += s.Shots
+		p.Hits += s.Hits
+		p.Kills += s.Kills
+		if s.Died < 0 {
+			p.BotsLeft++
+		}
+		report.Bots = append(report.Bots, *s)
+	}
+	for pid, t := range firstKills {
+		players[pid].FirstKill = t
+	}
+	for _, p := range players {
+		if p.Shots > 0 {
+			p.HitRate = float64(p.Hits) / float64(p.Shots)
+		}
+		report.Players = append(report.Players, *p)
+	}
+
+	sort.Slice(report.Players, func(i, j int) bool {
+		return report.Players[i].PID < report.Players[j].PID
+	})
+	sort.Slice(report.Bots, func(i, j int) bool {
+		a, b := report.Bots[i], report.Bots[j]
+		if a.PID != b.PID {
+			return a.PID < b.PID
+		}
+		return a.BID < b.BID
+	})
+
+	return report
+}
+
+// printReport writes the report as aligned text tables.
+func printReport(out io.Writer, report Report) {
+
+	fmt.Fprintf(out, "Our player ID:       %v\n", report.PID)
+	fmt.Fprintf(out, "Match length:        %.1fs\n", report.Duration)
+	fmt.Fprintf(out, "First kill:          %v\n", formatTime(report.FirstKill))
+	fmt.Fprintf(out, "Weapon range:        %.0f\n", report.Range)
+	fmt.Fprintf(out, "Unattributed damage: %v\n\n", report.Unattributed)
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "PID\tDealt\tTaken\tShots\tHits\tHit rate\tKills\tFirst kill\tBots left\t")
+	for _, p := range report.Players {
+		fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\t%.0f%%\t%v\t%v\t%v\t\n",
+			p.PID, p.DamageDealt, p.DamageTaken, p.Shots, p.Hits,
+			p.HitRate*100, p.Kills, formatTime(p.FirstKill), p.BotsLeft)
+	}
+	w.Flush()
+	fmt.Fprintln(out)
+
+	w = tabwriter.NewWriter(out, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "PID\tBID\tDealt\tTaken\tShots\tHits\tHit rate\tKills\tDied\tIn range\t")
+	for _, b := range report.Bots {
+		inRange := "-"
+		if b.PID == report.PID {
+			inRange = fmt.Sprintf("%.1fs", b.InRange)
+		}
+		fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\t%v\t%.0f%%\t%v\t%v\t%v\t\n",
+			b.PID, b.BID, b.DamageDealt, b.DamageTaken, b.Shots, b.Hits,
+			b.HitRate*100, b.Kills, formatTime(b.Died), inRange)
+	}
+	w.Flush()
+	fmt.Fprintln(out)
+
+	w = tabwriter.NewWriter(out, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "Time\tBID\tFPow\tMPow\tSPow\t")
+	for _, p := range report.Power {
+		fmt.Fprintf(w, "%.1fs\t%v\t%v\t%v\t%v\t\n", p.T, p.BID, p.FPow, p.MPow, p.SPow)
+	}
+	w.Flush()
+}
+
+// formatTime prints a time in seconds, or a dash if
+// it never happened.
+func formatTime(t float64) string {
+	if t < 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.1fs", t)
+}